@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"service-currency/internal"
+	"service-currency/internal/postgresql"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runAPIKey управляет ключами доступа к API:
+//
+//	service-currency apikey create [--owner O] [--description D] [--expires YYYY-MM-DD]
+//	                               [--scopes rates:latest,convert] [--plan P]
+//	                                     — выпустить ключ
+//	service-currency apikey list         — список ключей (без секретов)
+//	service-currency apikey revoke ID --reason R
+//	                                     — отозвать ключ навсегда
+//	service-currency apikey rotate ID    — выдать ключу новый секрет
+//
+// Секрет печатается один раз, в БД хранится только его HMAC с текущей версией ENCODING_KEY;
+// столбец PEPPER в list показывает версию, которой посчитан хеш.
+// Нужны DATABASE_URL и ENCODING_KEY (или ENCODING_KEYS); --plan проверяется по RATE_LIMIT_PLANS.
+func runAPIKey(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: apikey create|list|revoke ID --reason R|rotate ID")
+	}
+
+	dsn, err := LoadDatabaseURL()
+	if err != nil {
+		return err
+	}
+	peppers, err := LoadPeppers()
+	if err != nil {
+		return err
+	}
+	plans, err := LoadLimitPlans()
+	if err != nil {
+		return err
+	}
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("не удалось подключиться к БД: %w", err)
+	}
+	defer pool.Close()
+
+	keys := internal.NewAPIKeyManager(postgresql.NewAPIKeyStorage(pool), peppers).WithPlans(plans)
+
+	switch args[0] {
+	case "create":
+		meta, err := parseCreateFlags(args[1:])
+		if err != nil {
+			return err
+		}
+		raw, key, err := keys.Create(ctx, meta)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("id:  %d\nkey: %s\n", key.ID, raw)
+		fmt.Fprintln(os.Stderr, "the key is shown only once, store it now")
+		return nil
+
+	case "list":
+		list, err := keys.List(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "ID\tSTATUS\tOWNER\tSCOPES\tPLAN\tPEPPER\tCREATED\tEXPIRES\tLAST USED\tDESCRIPTION")
+		now := time.Now()
+		for _, k := range list {
+			expires := "-"
+			if k.ExpiresAt != nil {
+				expires = k.ExpiresAt.Format("2006-01-02 15:04:05")
+			}
+			lastUsed := "-"
+			if k.LastUsedAt != nil {
+				lastUsed = k.LastUsedAt.Format("2006-01-02 15:04:05")
+			}
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\tv%d\t%s\t%s\t%s\t%s\n",
+				k.ID, k.Status(now), k.Owner, strings.Join(k.Scopes, ","), k.Plan, k.HashVersion, k.CreatedAt.Format("2006-01-02 15:04:05"), expires, lastUsed, k.Description)
+		}
+		return tw.Flush()
+
+	case "revoke":
+		id, err := keyID(args)
+		if err != nil {
+			return err
+		}
+		fs := flag.NewFlagSet("apikey revoke", flag.ContinueOnError)
+		reason := fs.String("reason", "", "why the key is revoked (required)")
+		err = fs.Parse(args[2:])
+		if err != nil {
+			return err
+		}
+		if *reason == "" {
+			return errors.New("revoke: --reason is required")
+		}
+
+		err = keys.Revoke(ctx, id, *reason)
+		if err != nil {
+			return fmt.Errorf("revoke api key %d: %w", id, err)
+		}
+		fmt.Printf("revoked %d\n", id)
+		return nil
+
+	case "rotate":
+		id, err := keyID(args)
+		if err != nil {
+			return err
+		}
+		raw, err := keys.Rotate(ctx, id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("id:  %d\nkey: %s\n", id, raw)
+		fmt.Fprintln(os.Stderr, "the previous key no longer works; the new one is shown only once")
+		return nil
+
+	default:
+		return fmt.Errorf("unknown apikey command %q, expected create, list, revoke or rotate", args[0])
+	}
+}
+
+func parseCreateFlags(args []string) (internal.APIKey, error) {
+	fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	owner := fs.String("owner", "", "who the key is issued to")
+	description := fs.String("description", "", "what the key is for")
+	expires := fs.String("expires", "", "expiry date, YYYY-MM-DD (default: never)")
+	plan := fs.String("plan", internal.DefaultPlan, "rate limit plan, see RATE_LIMIT_PLANS")
+	scopes := fs.String("scopes", "", "comma-separated scopes (default: "+strings.Join(internal.DefaultScopes, ",")+")")
+	err := fs.Parse(args)
+	if err != nil {
+		return internal.APIKey{}, err
+	}
+
+	meta := internal.APIKey{Owner: *owner, Description: *description, Plan: *plan, CreatedBy: "cli"}
+	if u := os.Getenv("USER"); u != "" {
+		meta.CreatedBy = "cli:" + u
+	}
+	if *expires != "" {
+		d, err := parseFlagDate(*expires)
+		if err != nil {
+			return internal.APIKey{}, fmt.Errorf("--expires: %w", err)
+		}
+		meta.ExpiresAt = &d.Time
+	}
+	for _, s := range strings.Split(*scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			meta.Scopes = append(meta.Scopes, s)
+		}
+	}
+	return meta, nil
+}
+
+func keyID(args []string) (int64, error) {
+	if len(args) < 2 {
+		return 0, fmt.Errorf("%s: key id is required", args[0])
+	}
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("%s: invalid key id %q", args[0], args[1])
+	}
+	return id, nil
+}
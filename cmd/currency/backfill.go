@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"service-currency/internal"
+	"service-currency/internal/currency_freaks"
+	"service-currency/internal/postgresql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runBackfill загружает историю курсов за диапазон дат:
+//
+//	service-currency backfill --from 2020-01-01 --to 2024-12-31 [--symbols EUR,USD] [--concurrency 4]
+//
+// Прерванный запуск можно повторить с теми же флагами: уже сохранённые дни пропускаются.
+func runBackfill(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	fromRaw := fs.String("from", "", "first date, YYYY-MM-DD (required)")
+	toRaw := fs.String("to", "", "last date, YYYY-MM-DD (default: today)")
+	symbolsRaw := fs.String("symbols", "", "comma-separated quote currencies (default: SYMBOLS)")
+	concurrency := fs.Int("concurrency", 4, "parallel requests to the provider")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("не удалось загрузить конфиг: %w", err)
+	}
+	err = cfg.EnableCurrencies()
+	if err != nil {
+		return err
+	}
+
+	if *fromRaw == "" {
+		return errors.New("--from is required")
+	}
+	from, err := parseFlagDate(*fromRaw)
+	if err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	today := time.Now().In(cfg.Location)
+	to := internal.Date{Time: time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)}
+	if *toRaw != "" {
+		to, err = parseFlagDate(*toRaw)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+	}
+
+	symbols := cfg.Symbols
+	if *symbolsRaw != "" {
+		symbols, err = cfg.parseSymbols(*symbolsRaw)
+		if err != nil {
+			return fmt.Errorf("--symbols: %w", err)
+		}
+	}
+	if *concurrency < 1 {
+		return errors.New("--concurrency must be positive")
+	}
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("не удалось подключиться к БД: %w", err)
+	}
+	defer pool.Close()
+
+	storage := postgresql.NewCurrencyStorage(pool)
+	client := currencyFreaks.New(cfg.APIKey, storage)
+
+	report, err := internal.NewBackfill(storage, storage, client).
+		WithWorkers(*concurrency).
+		Run(ctx, cfg.BaseCCY, symbols, from, to)
+	log.Printf("backfill %s..%s: fetched=%d skipped=%d failed=%d",
+		from.Format("2006-01-02"), to.Format("2006-01-02"), len(report.Fetched), report.Skipped, len(report.Failed))
+	if err != nil {
+		return fmt.Errorf("backfill: %w", err)
+	}
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("backfill: %d days failed, run again to retry them", len(report.Failed))
+	}
+	return nil
+}
+
+func parseFlagDate(raw string) (internal.Date, error) {
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return internal.Date{}, errors.New("invalid date format, expected YYYY-MM-DD")
+	}
+	return internal.Date{Time: t}, nil
+}
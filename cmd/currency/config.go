@@ -1,78 +1,493 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 	"service-currency/internal"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/robfig/cron/v3"
 )
 
+const defaultConfigFile = ".env"
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
 type Config struct {
 	DatabaseURL string
 	APIKey      string
 
 	HTTPPort string
 
+	// Currencies — включённые валюты; пусто — весь реестр. Включает их EnableCurrencies.
+	Currencies []internal.CurrencyCode
+
 	BaseCCY internal.CurrencyCode
 	Symbols []internal.CurrencyCode
 
+	PivotCCY       internal.CurrencyCode
+	ConversionMode internal.ConversionMode
+	MixedDates     internal.MixedDatesPolicy
+
+	Rounding internal.RoundingRules
+
 	CronSpec string
-	Location string
+	Location *time.Location
 
-	EncodingKey string
+	ReconcileSpec string
+	ReconcileDays int
+
+	Peppers internal.Peppers
+
+	AdminTokens map[string]string
+
+	LimitPlans  internal.LimitPlans
+	RateLimiter string
+
+	APIKeyCacheTTL         time.Duration
+	APIKeyCacheNegativeTTL time.Duration
 }
 
+// Хранилища состояния лимитов, RATE_LIMITER.
+const (
+	rateLimiterMemory   = "memory"
+	rateLimiterPostgres = "postgres"
+)
+
+// LoadConfig читает конфиг из переменных окружения. Переменные можно положить в файл
+// формата .env: по умолчанию читается ./.env (если есть), другой путь задаётся CONFIG_FILE.
+//
+//	DATABASE_URL, CURRENCY_API_KEY, ENCODING_KEY — обязательные
+//	ENCODING_KEYS   — вместо ENCODING_KEY при смене секрета: версия:секрет через запятую,
+//	                  текущая — наибольшая версия ("2:new-secret,1:old-secret"); ENCODING_KEY — версия 1
+//	PORT            — порт HTTP (8080)
+//	CURRENCIES      — включённые валюты ISO 4217 через запятую (весь реестр)
+//	BASE_CURRENCY   — базовая валюта, в которой хранятся курсы (RUB)
+//	SYMBOLS         — котируемые валюты через запятую (EUR,USD,JPY)
+//	PIVOT_CURRENCY  — опорная валюта для кросс-курсов (BASE_CURRENCY)
+//	CONVERSION_MODE — pivot или path: через опорную валюту или поиском цепочки пар (pivot)
+//	MIXED_DATES     — allow, reject или common: ноги кросс-курса на разные даты (allow)
+//	RATE_ROUNDING   — округление курса в ответе, как в запросе: precision=N или significant_digits=N,
+//	                  rounding=half_up|bankers|truncate ("significant_digits=6&rounding=half_up")
+//	RATE_ROUNDING_<BASE>_<QUOTE> — то же для отдельной пары, например RATE_ROUNDING_RUB_USD=precision=4
+//	CRON_SPEC       — расписание обновления курсов, 5 полей cron ("0 12 * * *")
+//	TIMEZONE        — часовой пояс расписания (Europe/Moscow)
+//	RECONCILE_SPEC  — расписание поиска пропущенных дней в истории ("0 13 * * *")
+//	RECONCILE_DAYS  — сколько прошедших дней проверять (30)
+//	ADMIN_TOKENS    — доступ к /admin/v1: имя:токен через запятую; пусто — админский API выключен
+//	RATE_LIMIT_PLANS — лимиты тарифов через запятую: тариф:запросов_в_секунду/burst/квота_в_месяц,
+//	                  0 — без ограничения ("default:10/20/100000")
+//	RATE_LIMITER    — где держать состояние лимитов: memory (один экземпляр) или postgres (memory)
+//	API_KEY_CACHE_TTL — сколько помнить проверенный ключ, 0 — без кеша (30s)
+//	API_KEY_CACHE_NEGATIVE_TTL — сколько помнить неизвестный ключ (5s)
 func LoadConfig() (Config, error) {
-	err := godotenv.Overload()
+	err := loadConfigFile()
+	if err != nil {
+		return Config{}, err
+	}
+
+	var errs []error
+	cfg := Config{
+		HTTPPort: envOr("PORT", "8080"),
+		CronSpec: envOr("CRON_SPEC", "0 12 * * *"),
+
+		ReconcileSpec: envOr("RECONCILE_SPEC", "0 13 * * *"),
+	}
+
+	cfg.DatabaseURL = strings.TrimSpace(os.Getenv("DATABASE_URL"))
+	if cfg.DatabaseURL == "" {
+		errs = append(errs, errors.New("DATABASE_URL is empty"))
+	}
+
+	cfg.APIKey = strings.TrimSpace(os.Getenv("CURRENCY_API_KEY"))
+	if cfg.APIKey == "" {
+		errs = append(errs, errors.New("CURRENCY_API_KEY is empty"))
+	}
+
+	cfg.Peppers, err = parsePeppers()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	port, err := strconv.Atoi(cfg.HTTPPort)
+	if err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT: invalid port %q", cfg.HTTPPort))
+	}
+
+	// Включённые валюты разбираем до остальных кодов: currencyCode проверяет по ним.
+	cfg.Currencies, err = parseCurrencies(os.Getenv("CURRENCIES"))
 	if err != nil {
-		fmt.Println("Error loading .env file")
+		errs = append(errs, fmt.Errorf("CURRENCIES: %w", err))
 	}
 
-	rawBaseCCY := "RUB"
-	rawBaseSymbols := []string{"EUR", "USD", "JPY"}
+	cfg.BaseCCY, err = cfg.currencyCode(envOr("BASE_CURRENCY", "RUB"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("BASE_CURRENCY: %w", err))
+	}
 
-	baseCCY, err := internal.NewCurrencyCode(rawBaseCCY)
+	cfg.Symbols, err = cfg.parseSymbols(envOr("SYMBOLS", "EUR,USD,JPY"))
 	if err != nil {
-		fmt.Println("invalid base currency: %w", err)
+		errs = append(errs, fmt.Errorf("SYMBOLS: %w", err))
 	}
 
-	symbols := make([]internal.CurrencyCode, len(rawBaseSymbols))
-	for i, s := range rawBaseSymbols {
-		ccy, err := internal.NewCurrencyCode(s)
+	cfg.PivotCCY = cfg.BaseCCY
+	if p := strings.TrimSpace(os.Getenv("PIVOT_CURRENCY")); p != "" {
+		cfg.PivotCCY, err = cfg.currencyCode(p)
 		if err != nil {
-			fmt.Println("invalid symbol %q: %w", s, err)
+			errs = append(errs, fmt.Errorf("PIVOT_CURRENCY: %w", err))
 		}
-		symbols[i] = ccy
 	}
 
-	cfg := Config{
-		HTTPPort: "8080",
-		BaseCCY:  baseCCY,
-		Symbols:  symbols,
-		CronSpec: "0 12 * * *",
-		Location: "Europe/Moscow",
+	cfg.ConversionMode = internal.ConversionMode(strings.ToLower(envOr("CONVERSION_MODE", string(internal.ConversionPivot))))
+	if cfg.ConversionMode != internal.ConversionPivot && cfg.ConversionMode != internal.ConversionPath {
+		errs = append(errs, fmt.Errorf("CONVERSION_MODE: expected %q or %q, got %q",
+			internal.ConversionPivot, internal.ConversionPath, cfg.ConversionMode))
 	}
 
-	cfg.DatabaseURL = strings.TrimSpace(os.Getenv("DATABASE_URL"))
-	if cfg.DatabaseURL == "" {
-		return Config{}, fmt.Errorf("DATABASE_URL is empty")
+	cfg.MixedDates = internal.MixedDatesPolicy(strings.ToLower(envOr("MIXED_DATES", string(internal.MixedDatesAllow))))
+	switch cfg.MixedDates {
+	case internal.MixedDatesAllow, internal.MixedDatesReject, internal.MixedDatesCommon:
+	default:
+		errs = append(errs, fmt.Errorf("MIXED_DATES: expected %q, %q or %q, got %q",
+			internal.MixedDatesAllow, internal.MixedDatesReject, internal.MixedDatesCommon, cfg.MixedDates))
 	}
 
-	cfg.APIKey = strings.TrimSpace(os.Getenv("CURRENCY_API_KEY"))
-	if cfg.APIKey == "" {
-		return Config{}, fmt.Errorf("CURRENCY_API_KEY is empty")
+	cfg.Rounding, err = cfg.parseRoundingRules()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = cronParser.Parse(cfg.CronSpec)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("CRON_SPEC: invalid spec %q: %w", cfg.CronSpec, err))
+	}
+
+	_, err = cronParser.Parse(cfg.ReconcileSpec)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("RECONCILE_SPEC: invalid spec %q: %w", cfg.ReconcileSpec, err))
+	}
+
+	days := envOr("RECONCILE_DAYS", "30")
+	cfg.ReconcileDays, err = strconv.Atoi(days)
+	if err != nil || cfg.ReconcileDays < 1 {
+		errs = append(errs, fmt.Errorf("RECONCILE_DAYS: expected positive integer, got %q", days))
+	}
+
+	cfg.AdminTokens, err = parseAdminTokens(os.Getenv("ADMIN_TOKENS"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("ADMIN_TOKENS: %w", err))
 	}
 
-	cfg.EncodingKey = strings.TrimSpace(os.Getenv("ENCODING_KEY"))
-	if cfg.EncodingKey == "" {
-		return Config{}, fmt.Errorf("ENCODING_KEY is empty")
+	cfg.LimitPlans, err = parseRateLimitPlansEnv()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	cfg.RateLimiter = strings.ToLower(envOr("RATE_LIMITER", rateLimiterMemory))
+	if cfg.RateLimiter != rateLimiterMemory && cfg.RateLimiter != rateLimiterPostgres {
+		errs = append(errs, fmt.Errorf("RATE_LIMITER: expected %q or %q, got %q",
+			rateLimiterMemory, rateLimiterPostgres, cfg.RateLimiter))
+	}
+
+	cfg.APIKeyCacheTTL, err = parseDuration("API_KEY_CACHE_TTL", "30s")
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.APIKeyCacheNegativeTTL, err = parseDuration("API_KEY_CACHE_NEGATIVE_TTL", "5s")
+	if err != nil {
+		errs = append(errs, err)
 	}
 
-	if p := strings.TrimSpace(os.Getenv("PORT")); p != "" {
-		cfg.HTTPPort = p
+	tz := envOr("TIMEZONE", "Europe/Moscow")
+	cfg.Location, err = time.LoadLocation(tz)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("TIMEZONE: %w", err))
 	}
 
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
 	return cfg, nil
 }
+
+// LoadDatabaseURL читает только DATABASE_URL — для команд, которым не нужен остальной конфиг.
+func LoadDatabaseURL() (string, error) {
+	err := loadConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	dsn := strings.TrimSpace(os.Getenv("DATABASE_URL"))
+	if dsn == "" {
+		return "", errors.New("DATABASE_URL is empty")
+	}
+	return dsn, nil
+}
+
+// LoadPeppers читает ENCODING_KEYS или ENCODING_KEY — секреты HMAC, которыми хешируются API-ключи.
+func LoadPeppers() (internal.Peppers, error) {
+	err := loadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	return parsePeppers()
+}
+
+// LoadLimitPlans читает RATE_LIMIT_PLANS — тарифы, на которые можно выпускать ключи.
+func LoadLimitPlans() (internal.LimitPlans, error) {
+	err := loadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	return parseRateLimitPlansEnv()
+}
+
+func parseRateLimitPlansEnv() (internal.LimitPlans, error) {
+	plans, err := parseLimitPlans(envOr("RATE_LIMIT_PLANS", "default:10/20/100000"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_PLANS: %w", err)
+	}
+	return plans, nil
+}
+
+func parsePeppers() (internal.Peppers, error) {
+	single := strings.TrimSpace(os.Getenv("ENCODING_KEY"))
+	versioned := strings.TrimSpace(os.Getenv("ENCODING_KEYS"))
+	switch {
+	case single != "" && versioned != "":
+		return nil, errors.New("ENCODING_KEY and ENCODING_KEYS are both set, keep only ENCODING_KEYS")
+	case versioned == "" && single == "":
+		return nil, errors.New("ENCODING_KEY is empty")
+	case versioned == "":
+		return internal.NewPeppers(internal.Pepper{Version: 1, Key: single})
+	}
+
+	var list []internal.Pepper
+	for _, item := range splitList(versioned) {
+		rawVersion, key, ok := strings.Cut(item, ":")
+		version, err := strconv.Atoi(strings.TrimSpace(rawVersion))
+		if !ok || err != nil {
+			// сам секрет в сообщение не попадает
+			return nil, fmt.Errorf("ENCODING_KEYS: expected version:key, got item starting with %q", rawVersion)
+		}
+		list = append(list, internal.Pepper{Version: version, Key: key})
+	}
+	peppers, err := internal.NewPeppers(list...)
+	if err != nil {
+		return nil, fmt.Errorf("ENCODING_KEYS: %w", err)
+	}
+	return peppers, nil
+}
+
+func loadConfigFile() error {
+	path := strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+
+	err := godotenv.Overload(path)
+	if err != nil {
+		// файл по умолчанию необязателен: в контейнере переменные приходят через env_file
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("load config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// EnableCurrencies включает валюты из CURRENCIES для всего процесса. LoadConfig этого не делает:
+// он только читает конфиг, а включённые валюты — глобальное состояние пакета internal.
+func (c Config) EnableCurrencies() error {
+	codes := make([]string, len(c.Currencies))
+	for i, ccy := range c.Currencies {
+		codes[i] = ccy.String()
+	}
+	return internal.EnableCurrencies(codes...)
+}
+
+func parseCurrencies(raw string) ([]internal.CurrencyCode, error) {
+	var out []internal.CurrencyCode
+	for _, s := range splitList(raw) {
+		info, ok := internal.LookupCurrency(s)
+		if !ok {
+			return nil, fmt.Errorf("unknown currency %q", s)
+		}
+		out = append(out, info.Code)
+	}
+	return out, nil
+}
+
+// currencyCode разбирает код валюты так же, как internal.NewCurrencyCode после EnableCurrencies.
+func (c Config) currencyCode(s string) (internal.CurrencyCode, error) {
+	info, ok := internal.LookupCurrency(s)
+	if !ok || (len(c.Currencies) > 0 && !slices.Contains(c.Currencies, info.Code)) {
+		return "", fmt.Errorf("unsupported currency %q", s)
+	}
+	return info.Code, nil
+}
+
+func (c Config) parseSymbols(raw string) ([]internal.CurrencyCode, error) {
+	parts := splitList(raw)
+	if len(parts) == 0 {
+		return nil, errors.New("list is empty")
+	}
+
+	seen := make(map[internal.CurrencyCode]struct{}, len(parts))
+	symbols := make([]internal.CurrencyCode, 0, len(parts))
+	for _, s := range parts {
+		ccy, err := c.currencyCode(s)
+		if err != nil {
+			return nil, err
+		}
+		if ccy == c.BaseCCY {
+			return nil, fmt.Errorf("symbol %s equals base currency", ccy)
+		}
+		if _, dup := seen[ccy]; dup {
+			return nil, fmt.Errorf("duplicate symbol %s", ccy)
+		}
+		seen[ccy] = struct{}{}
+		symbols = append(symbols, ccy)
+	}
+	return symbols, nil
+}
+
+// minAdminTokenLen — короткий токен легко подобрать.
+const minAdminTokenLen = 32
+
+func parseAdminTokens(raw string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	seen := make(map[string]struct{})
+	for _, item := range splitList(raw) {
+		name, token, ok := strings.Cut(item, ":")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("expected name:token, got %q", item)
+		}
+		if len(token) < minAdminTokenLen {
+			return nil, fmt.Errorf("token of %s is shorter than %d characters", name, minAdminTokenLen)
+		}
+		if _, dup := tokens[name]; dup {
+			return nil, fmt.Errorf("duplicate admin %s", name)
+		}
+		if _, dup := seen[token]; dup {
+			return nil, fmt.Errorf("token of %s is used by another admin", name)
+		}
+		tokens[name] = token
+		seen[token] = struct{}{}
+	}
+	return tokens, nil
+}
+
+// parseLimitPlans разбирает "default:10/20/100000,pro:50/100/0".
+func parseLimitPlans(raw string) (internal.LimitPlans, error) {
+	plans := make(internal.LimitPlans)
+	for _, item := range splitList(raw) {
+		plan, spec, ok := strings.Cut(item, ":")
+		plan = strings.TrimSpace(plan)
+		parts := strings.Split(spec, "/")
+		if !ok || plan == "" || len(parts) != 3 {
+			return nil, fmt.Errorf("expected plan:rate/burst/quota, got %q", item)
+		}
+
+		rate, errRate := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		burst, errBurst := strconv.Atoi(strings.TrimSpace(parts[1]))
+		quota, errQuota := strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64)
+		if err := errors.Join(errRate, errBurst, errQuota); err != nil {
+			return nil, fmt.Errorf("plan %s: %w", plan, err)
+		}
+		l := internal.Limits{RatePerSecond: rate, Burst: burst, MonthlyQuota: quota}
+		if err := internal.CheckLimits(&l); err != nil {
+			return nil, fmt.Errorf("plan %s: %w", plan, err)
+		}
+		if _, dup := plans[plan]; dup {
+			return nil, fmt.Errorf("duplicate plan %s", plan)
+		}
+		plans[plan] = l
+	}
+	return plans, nil
+}
+
+const roundingEnv = "RATE_ROUNDING"
+
+func (c Config) parseRoundingRules() (internal.RoundingRules, error) {
+	base := internal.Rounding{Mode: internal.RoundHalfUp}
+
+	def, err := parseRounding(envOr(roundingEnv, "significant_digits=6&rounding=half_up"), base)
+	if err != nil {
+		return internal.RoundingRules{}, fmt.Errorf("%s: %w", roundingEnv, err)
+	}
+	rules := internal.RoundingRules{Default: def, Pairs: map[[2]internal.CurrencyCode]internal.Rounding{}}
+
+	for _, kv := range os.Environ() {
+		key, spec, _ := strings.Cut(kv, "=")
+		pair, ok := strings.CutPrefix(key, roundingEnv+"_")
+		if !ok {
+			continue
+		}
+
+		rawBase, rawQuote, ok := strings.Cut(pair, "_")
+		if !ok {
+			return internal.RoundingRules{}, fmt.Errorf("%s: expected %s_<BASE>_<QUOTE>", key, roundingEnv)
+		}
+		b, err := c.currencyCode(rawBase)
+		if err != nil {
+			return internal.RoundingRules{}, fmt.Errorf("%s: %w", key, err)
+		}
+		q, err := c.currencyCode(rawQuote)
+		if err != nil {
+			return internal.RoundingRules{}, fmt.Errorf("%s: %w", key, err)
+		}
+
+		rule, err := parseRounding(spec, def)
+		if err != nil {
+			return internal.RoundingRules{}, fmt.Errorf("%s: %w", key, err)
+		}
+		rules.Pairs[[2]internal.CurrencyCode{b, q}] = rule
+	}
+	return rules, nil
+}
+
+func parseRounding(spec string, def internal.Rounding) (internal.Rounding, error) {
+	v, err := url.ParseQuery(strings.TrimSpace(spec))
+	if err != nil {
+		return internal.Rounding{}, fmt.Errorf("parse %q: %w", spec, err)
+	}
+	return internal.ParseRounding(v, def)
+}
+
+func parseDuration(key, def string) (time.Duration, error) {
+	raw := envOr(key, def)
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s: expected non-negative duration like 30s, got %q", key, raw)
+	}
+	return d, nil
+}
+
+func envOr(key, def string) string {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"service-currency/internal"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configEnv — все переменные, которые читает LoadConfig; обязательные заполнены.
+func configEnv(t *testing.T) map[string]string {
+	t.Helper()
+
+	// пустой файл вместо ./.env, чтобы тесты не зависели от окружения разработчика
+	file := filepath.Join(t.TempDir(), "test.env")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	env := map[string]string{
+		"CONFIG_FILE":      file,
+		"DATABASE_URL":     "postgres://localhost/currency",
+		"CURRENCY_API_KEY": "upstream-key",
+		"ENCODING_KEY":     "pepper",
+	}
+	for _, key := range []string{
+		"PORT", "CURRENCIES", "BASE_CURRENCY", "SYMBOLS", "PIVOT_CURRENCY", "CONVERSION_MODE",
+		"MIXED_DATES", "RATE_ROUNDING", "CRON_SPEC", "TIMEZONE", "RECONCILE_SPEC", "RECONCILE_DAYS",
+		"ADMIN_TOKENS", "RATE_LIMIT_PLANS", "RATE_LIMITER", "API_KEY_CACHE_TTL",
+		"API_KEY_CACHE_NEGATIVE_TTL", "ENCODING_KEYS",
+	} {
+		env[key] = ""
+	}
+	// RATE_ROUNDING_<BASE>_<QUOTE> перебираются по os.Environ: пустое значение — тоже правило
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasPrefix(key, roundingEnv+"_") {
+			t.Setenv(key, "") // вернёт значение после теста
+			require.NoError(t, os.Unsetenv(key))
+		}
+	}
+	return env
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	for k, v := range configEnv(t) {
+		t.Setenv(k, v)
+	}
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, "8080", cfg.HTTPPort)
+	assert.Empty(t, cfg.Currencies)
+	assert.Equal(t, internal.CurrencyCode("RUB"), cfg.BaseCCY)
+	assert.Equal(t, []internal.CurrencyCode{"EUR", "USD", "JPY"}, cfg.Symbols)
+	assert.Equal(t, cfg.BaseCCY, cfg.PivotCCY)
+	assert.Equal(t, internal.ConversionPivot, cfg.ConversionMode)
+	assert.Equal(t, internal.MixedDatesAllow, cfg.MixedDates)
+	assert.Equal(t, "0 12 * * *", cfg.CronSpec)
+	assert.Equal(t, "Europe/Moscow", cfg.Location.String())
+	assert.Equal(t, 30, cfg.ReconcileDays)
+	assert.Empty(t, cfg.AdminTokens)
+	assert.Equal(t, internal.Limits{RatePerSecond: 10, Burst: 20, MonthlyQuota: 100000}, cfg.LimitPlans[internal.DefaultPlan])
+	assert.Equal(t, rateLimiterMemory, cfg.RateLimiter)
+	assert.Equal(t, 30*time.Second, cfg.APIKeyCacheTTL)
+	assert.Equal(t, 5*time.Second, cfg.APIKeyCacheNegativeTTL)
+	assert.Equal(t, internal.Peppers{{Version: 1, Key: "pepper"}}, cfg.Peppers)
+}
+
+func TestLoadConfig(t *testing.T) {
+	token := strings.Repeat("t", minAdminTokenLen)
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string // пусто — конфиг валиден
+		check   func(t *testing.T, cfg Config)
+	}{
+		{
+			name: "custom base and symbols",
+			env:  map[string]string{"BASE_CURRENCY": "usd", "SYMBOLS": " eur , gbp "},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, internal.CurrencyCode("USD"), cfg.BaseCCY)
+				assert.Equal(t, []internal.CurrencyCode{"EUR", "GBP"}, cfg.Symbols)
+			},
+		},
+		{
+			name: "enabled currencies",
+			env:  map[string]string{"CURRENCIES": "rub, eur,usd", "SYMBOLS": "EUR,USD"},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, []internal.CurrencyCode{"RUB", "EUR", "USD"}, cfg.Currencies)
+				// LoadConfig только читает конфиг: включает валюты Config.EnableCurrencies
+				gbp := internal.CurrencyCode("GBP")
+				assert.True(t, gbp.IsSupported())
+			},
+		},
+		{
+			name: "pair rounding",
+			env:  map[string]string{"RATE_ROUNDING_RUB_USD": "precision=4"},
+			check: func(t *testing.T, cfg Config) {
+				rule := cfg.Rounding.Pairs[[2]internal.CurrencyCode{"RUB", "USD"}]
+				require.NotNil(t, rule.Precision)
+				assert.Equal(t, int32(4), *rule.Precision)
+			},
+		},
+		{
+			name: "admin tokens",
+			env:  map[string]string{"ADMIN_TOKENS": "alice:" + token},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, map[string]string{"alice": token}, cfg.AdminTokens)
+			},
+		},
+		{
+			name: "rate limit plans",
+			env:  map[string]string{"RATE_LIMIT_PLANS": "default:1/1/0,pro:50/100/0"},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, internal.Limits{RatePerSecond: 50, Burst: 100}, cfg.LimitPlans["pro"])
+			},
+		},
+		{
+			name: "versioned encoding keys",
+			env:  map[string]string{"ENCODING_KEY": "", "ENCODING_KEYS": "1:old,2:new"},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, internal.Pepper{Version: 2, Key: "new"}, cfg.Peppers.Current())
+			},
+		},
+		{name: "missing database url", env: map[string]string{"DATABASE_URL": ""}, wantErr: "DATABASE_URL is empty"},
+		{name: "missing upstream key", env: map[string]string{"CURRENCY_API_KEY": ""}, wantErr: "CURRENCY_API_KEY is empty"},
+		{name: "missing encoding key", env: map[string]string{"ENCODING_KEY": ""}, wantErr: "ENCODING_KEY is empty"},
+		{name: "both encoding key forms", env: map[string]string{"ENCODING_KEYS": "1:x"}, wantErr: "ENCODING_KEY and ENCODING_KEYS are both set"},
+		{name: "invalid port", env: map[string]string{"PORT": "70000"}, wantErr: `PORT: invalid port "70000"`},
+		{name: "unknown currency", env: map[string]string{"CURRENCIES": "RUB,XYZ"}, wantErr: `CURRENCIES: unknown currency "XYZ"`},
+		{name: "symbol not enabled", env: map[string]string{"CURRENCIES": "RUB,EUR"}, wantErr: `SYMBOLS: unsupported currency "USD"`},
+		{name: "unknown base", env: map[string]string{"BASE_CURRENCY": "XYZ"}, wantErr: "BASE_CURRENCY"},
+		{name: "symbol equals base", env: map[string]string{"SYMBOLS": "EUR,RUB"}, wantErr: "symbol RUB equals base currency"},
+		{name: "duplicate symbol", env: map[string]string{"SYMBOLS": "EUR,eur"}, wantErr: "duplicate symbol EUR"},
+		{name: "unknown pivot", env: map[string]string{"PIVOT_CURRENCY": "XYZ"}, wantErr: "PIVOT_CURRENCY"},
+		{name: "conversion mode", env: map[string]string{"CONVERSION_MODE": "graph"}, wantErr: "CONVERSION_MODE"},
+		{name: "mixed dates", env: map[string]string{"MIXED_DATES": "maybe"}, wantErr: "MIXED_DATES"},
+		{name: "rounding", env: map[string]string{"RATE_ROUNDING": "precision=x"}, wantErr: "RATE_ROUNDING"},
+		{name: "pair rounding currency", env: map[string]string{"RATE_ROUNDING_RUB_XYZ": "precision=2"}, wantErr: "RATE_ROUNDING_RUB_XYZ"},
+		{name: "cron spec", env: map[string]string{"CRON_SPEC": "every day"}, wantErr: "CRON_SPEC: invalid spec"},
+		{name: "reconcile days", env: map[string]string{"RECONCILE_DAYS": "0"}, wantErr: "RECONCILE_DAYS"},
+		{name: "timezone", env: map[string]string{"TIMEZONE": "Mars/Olympus"}, wantErr: "TIMEZONE"},
+		{name: "short admin token", env: map[string]string{"ADMIN_TOKENS": "alice:short"}, wantErr: "token of alice is shorter"},
+		{name: "rate limit plan", env: map[string]string{"RATE_LIMIT_PLANS": "default:10/0/0"}, wantErr: "burst must be at least 1"},
+		{name: "rate limiter", env: map[string]string{"RATE_LIMITER": "redis"}, wantErr: "RATE_LIMITER"},
+		{name: "cache ttl", env: map[string]string{"API_KEY_CACHE_TTL": "-1s"}, wantErr: "API_KEY_CACHE_TTL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := configEnv(t)
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := LoadConfig()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
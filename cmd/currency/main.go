@@ -16,6 +16,7 @@ import (
 	"syscall"
 	"time"
 
+	adminhttp "service-currency/internal/api/http/admin"
 	rateshttp "service-currency/internal/api/http/rates"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -27,7 +28,23 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	err := run(ctx)
+	var err error
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+	switch cmd {
+	case "serve":
+		err = run(ctx)
+	case "backfill":
+		err = runBackfill(ctx, args)
+	case "migrate":
+		err = runMigrate(ctx, args)
+	case "apikey":
+		err = runAPIKey(ctx, args)
+	default:
+		err = fmt.Errorf("unknown command %q, expected serve, backfill, migrate or apikey", cmd)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -39,6 +56,10 @@ func run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("не удалось загрузить конфиг: %w", err)
 	}
+	err = cfg.EnableCurrencies()
+	if err != nil {
+		return err
+	}
 
 	// DB
 	dbCtx, cancelDB := context.WithTimeout(ctx, 5*time.Second)
@@ -53,10 +74,12 @@ func run(ctx context.Context) error {
 	// storage + migrations
 	storage := postgresql.NewCurrencyStorage(pool)
 	apiKeyStorage := postgresql.NewAPIKeyStorage(pool)
-	migrator := migrations.New(pool)
-	err = migrator.Setup(dbCtx)
+	applied, err := migrations.New(pool).Up(ctx)
 	if err != nil {
-		return fmt.Errorf("ensure tables: %w", err)
+		return fmt.Errorf("migrate up: %w", err)
+	}
+	for _, mig := range applied {
+		log.Printf("migration applied: %04d_%s", mig.Version, mig.Name)
 	}
 
 	// client
@@ -71,14 +94,9 @@ func run(ctx context.Context) error {
 	log.Printf("rates updated (base=%s date=%s)", resp.Base, resp.Date)
 
 	// cron
-	loc, err := time.LoadLocation(cfg.Location)
-	if err != nil {
-		return fmt.Errorf("load location %s: %w", cfg.Location, err)
-	}
-
 	scheduler := cron.New(
-		cron.WithLocation(loc),
-		cron.WithParser(cron.NewParser(cron.Minute|cron.Hour|cron.Dom|cron.Month|cron.Dow)),
+		cron.WithLocation(cfg.Location),
+		cron.WithParser(cronParser),
 	)
 
 	// logger
@@ -86,16 +104,40 @@ func run(ctx context.Context) error {
 	reqAuditLogger := internal.NewStorageAuditLogger(reqAuditStorage)
 
 	// HTTP handler
-	ratesService := internal.NewRateConverter(storage)
-	ratesHandler := rateshttp.New(ratesService, reqAuditLogger, client, cfg.Symbols)
+	historicalService := internal.NewHistoricalRates(storage, storage, client)
+	ratesService := internal.NewRateConverter(storage).
+		WithPivot(cfg.PivotCCY).
+		WithMode(cfg.ConversionMode).
+		WithMixedDates(cfg.MixedDates).
+		WithHistory(historicalService)
+	ratesHandler := rateshttp.New(ratesService, historicalService, reqAuditLogger, cfg.Symbols, cfg.Rounding)
 
-	mux := http.NewServeMux()
+	apiMux := http.NewServeMux()
+	ratesHandler.Register(apiMux)
 
 	// Middleware
-	apiKeyValidator := internal.NewAPIKeyValidator(apiKeyStorage, cfg.EncodingKey)
+	apiKeyUsage := internal.NewAPIKeyUsage(apiKeyStorage)
+	apiKeyCache := internal.NewCachedAPIKeyRepository(apiKeyStorage, cfg.APIKeyCacheTTL, cfg.APIKeyCacheNegativeTTL)
+	apiKeyValidator := internal.NewAPIKeyValidator(apiKeyCache, cfg.Peppers, apiKeyUsage)
 	authMiddleware := middleware.APIKeyAuth(apiKeyValidator)
-	mw := []func(next http.Handler) http.Handler{authMiddleware}
-	ratesHandler.Register(mux)
+
+	var limiter internal.RateLimiter = internal.NewMemoryRateLimiter()
+	if cfg.RateLimiter == rateLimiterPostgres {
+		limiter = postgresql.NewRateLimiter(pool)
+	}
+	rateLimitMiddleware := middleware.RateLimit(limiter, cfg.LimitPlans)
+	mw := []func(next http.Handler) http.Handler{authMiddleware, rateLimitMiddleware}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", chain(apiMux, mw))
+
+	// admin: свой токен вместо X-API-Key
+	if len(cfg.AdminTokens) > 0 {
+		adminMux := http.NewServeMux()
+		keyManager := internal.NewAPIKeyManager(apiKeyStorage, cfg.Peppers).WithPlans(cfg.LimitPlans)
+		adminhttp.New(keyManager, postgresql.NewAdminAuditStorage(pool)).Register(adminMux)
+		mux.Handle("/admin/", middleware.AdminAuth(cfg.AdminTokens)(adminMux))
+	}
 
 	ewg, gctx := errgroup.WithContext(ctx)
 
@@ -111,12 +153,39 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("add cron func: %w", err)
 	}
 
+	// reconciler
+	reconciler := internal.NewReconciler(storage, historicalService, postgresql.NewRepairLogStorage(pool)).
+		WithDays(cfg.ReconcileDays).
+		WithClock(func() time.Time { return time.Now().In(cfg.Location) })
+
+	_, err = scheduler.AddFunc(cfg.ReconcileSpec, func() {
+		reconciler.RunAndLog(gctx, cfg.BaseCCY, cfg.Symbols)
+	})
+	if err != nil {
+		return fmt.Errorf("add reconcile func: %w", err)
+	}
+
 	ewg.Go(func() error {
 		return runCron(gctx, scheduler)
 	})
 
 	ewg.Go(func() error {
-		return serveHTTP(gctx, ":"+cfg.HTTPPort, mux, mw)
+		return apiKeyUsage.Run(gctx, time.Minute)
+	})
+
+	// отзыв и прочие изменения ключей сбрасывают кеш сразу, не дожидаясь TTL
+	ewg.Go(func() error {
+		return postgresql.NewAPIKeyListener(pool).Run(gctx, apiKeyCache.Invalidate, apiKeyCache.InvalidateAll)
+	})
+
+	// на старте — в фоне, чтобы не задерживать HTTP
+	ewg.Go(func() error {
+		reconciler.RunAndLog(gctx, cfg.BaseCCY, cfg.Symbols)
+		return nil
+	})
+
+	ewg.Go(func() error {
+		return serveHTTP(gctx, ":"+cfg.HTTPPort, mux)
 	})
 
 	log.Println("Running. Stop with Ctrl+C / SIGTERM.")
@@ -135,11 +204,15 @@ func runCron(ctx context.Context, c *cron.Cron) error {
 	return nil
 }
 
-func serveHTTP(ctx context.Context, addr string, h http.Handler, mws []func(http.Handler) http.Handler) error {
+// chain оборачивает h в mws: первый в списке выполняется первым.
+func chain(h http.Handler, mws []func(http.Handler) http.Handler) http.Handler {
 	for i := len(mws) - 1; i >= 0; i-- {
 		h = mws[i](h)
 	}
+	return h
+}
 
+func serveHTTP(ctx context.Context, addr string, h http.Handler) error {
 	srv := &http.Server{Addr: addr, Handler: h}
 
 	go func() {
@@ -0,0 +1,16 @@
+package internal
+
+import "context"
+
+// AdminAuditEntry — запись о действии администратора. Пишется отдельно от request_log:
+// журнал запросов к курсам и журнал изменений ключей читают разные люди.
+type AdminAuditEntry struct {
+	Actor  string
+	Action string
+	KeyID  *int64
+	Status int
+}
+
+type AdminAuditStorage interface {
+	InsertAdminAudit(ctx context.Context, entry AdminAuditEntry) error
+}
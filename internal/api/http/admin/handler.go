@@ -0,0 +1,293 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"service-currency/internal"
+	"service-currency/internal/api/http/middleware"
+)
+
+// Handler — управление API-ключами. Монтируется за middleware.AdminAuth.
+type Handler struct {
+	keys  *internal.APIKeyManager
+	audit internal.AdminAuditStorage
+}
+
+func New(keys *internal.APIKeyManager, audit internal.AdminAuditStorage) *Handler {
+	return &Handler{keys: keys, audit: audit}
+}
+
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/admin/v1/keys", h.keysRoot)
+	mux.HandleFunc("/admin/v1/keys/{id}", h.deleteKey)
+	mux.HandleFunc("/admin/v1/keys/{id}/deactivate", h.setActive(false))
+	mux.HandleFunc("/admin/v1/keys/{id}/reactivate", h.setActive(true))
+	mux.HandleFunc("/admin/v1/keys/{id}/revoke", h.revokeKey)
+	mux.HandleFunc("/admin/v1/keys/{id}/plan", h.setPlan)
+}
+
+type createRequest struct {
+	Owner       string     `json:"owner"`
+	Description string     `json:"description"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// Scopes — права ключа; пусто — internal.DefaultScopes.
+	Scopes []string `json:"scopes,omitempty"`
+	// Plan — тариф ключа; пусто — internal.DefaultPlan.
+	Plan   string           `json:"plan,omitempty"`
+	Limits *internal.Limits `json:"limits,omitempty"`
+}
+
+type createResponse struct {
+	internal.APIKey
+	// Key показывается один раз: в БД хранится только его HMAC.
+	Key string `json:"key"`
+}
+
+func (h *Handler) keysRoot(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listKeys(w, r)
+	case http.MethodPost:
+		h.createKey(w, r)
+	default:
+		h.fail(w, r, "keys", nil, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *Handler) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.keys.List(r.Context())
+	if err != nil {
+		log.Printf("admin list keys failed: %v", err)
+		h.fail(w, r, "list", nil, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if keys == nil {
+		keys = []internal.APIKey{}
+	}
+	h.respond(w, r, "list", nil, http.StatusOK, keys)
+}
+
+func (h *Handler) createKey(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req)
+	if err != nil {
+		h.fail(w, r, "create", nil, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Owner == "" {
+		h.fail(w, r, "create", nil, http.StatusBadRequest, "owner is required")
+		return
+	}
+
+	admin, _ := middleware.AdminFromContext(r.Context())
+	raw, key, err := h.keys.Create(r.Context(), internal.APIKey{
+		Owner:       req.Owner,
+		Description: req.Description,
+		CreatedBy:   admin,
+		ExpiresAt:   req.ExpiresAt,
+		Scopes:      req.Scopes,
+		Plan:        req.Plan,
+		Limits:      req.Limits,
+	})
+	if errors.Is(err, internal.ErrInvalidKeyParams) {
+		h.fail(w, r, "create", nil, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("admin create key failed: %v", err)
+		h.fail(w, r, "create", nil, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	h.respond(w, r, "create", &key.ID, http.StatusCreated, createResponse{APIKey: key, Key: raw})
+}
+
+func (h *Handler) setActive(active bool) http.HandlerFunc {
+	action := "deactivate"
+	if active {
+		action = "reactivate"
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			h.fail(w, r, action, nil, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, ok := h.keyID(w, r, action)
+		if !ok {
+			return
+		}
+
+		var err error
+		if active {
+			err = h.keys.Reactivate(r.Context(), id)
+		} else {
+			err = h.keys.Suspend(r.Context(), id)
+		}
+		if err != nil {
+			h.storeErr(w, r, action, id, err)
+			return
+		}
+		h.respond(w, r, action, &id, http.StatusNoContent, nil)
+	}
+}
+
+type revokeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// revokeKey отзывает ключ навсегда, в отличие от deactivate.
+func (h *Handler) revokeKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.fail(w, r, "revoke", nil, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, ok := h.keyID(w, r, "revoke")
+	if !ok {
+		return
+	}
+
+	var req revokeRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req)
+	if err != nil || req.Reason == "" {
+		h.fail(w, r, "revoke", &id, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	err = h.keys.Revoke(r.Context(), id, req.Reason)
+	if err != nil {
+		h.storeErr(w, r, "revoke", id, err)
+		return
+	}
+	h.respond(w, r, "revoke", &id, http.StatusNoContent, nil)
+}
+
+type planRequest struct {
+	Plan string `json:"plan"`
+	// Limits — свои ограничения ключа; null — по тарифу.
+	Limits *internal.Limits `json:"limits"`
+}
+
+// setPlan меняет тариф и собственные лимиты ключа.
+func (h *Handler) setPlan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.fail(w, r, "plan", nil, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, ok := h.keyID(w, r, "plan")
+	if !ok {
+		return
+	}
+
+	var req planRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req)
+	if err != nil || req.Plan == "" {
+		h.fail(w, r, "plan", &id, http.StatusBadRequest, "plan is required")
+		return
+	}
+	err = internal.CheckLimits(req.Limits)
+	if err != nil {
+		h.fail(w, r, "plan", &id, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.keys.SetPlan(r.Context(), id, req.Plan, req.Limits)
+	if errors.Is(err, internal.ErrInvalidKeyParams) {
+		h.fail(w, r, "plan", &id, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.storeErr(w, r, "plan", id, err)
+		return
+	}
+	h.respond(w, r, "plan", &id, http.StatusNoContent, nil)
+}
+
+func (h *Handler) deleteKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.fail(w, r, "delete", nil, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, ok := h.keyID(w, r, "delete")
+	if !ok {
+		return
+	}
+
+	err := h.keys.Delete(r.Context(), id)
+	if err != nil {
+		h.storeErr(w, r, "delete", id, err)
+		return
+	}
+	h.respond(w, r, "delete", &id, http.StatusNoContent, nil)
+}
+
+func (h *Handler) keyID(w http.ResponseWriter, r *http.Request, action string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		h.fail(w, r, action, nil, http.StatusBadRequest, "invalid key id")
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) storeErr(w http.ResponseWriter, r *http.Request, action string, id int64, err error) {
+	if errors.Is(err, internal.ErrAPIKeyNotFound) {
+		h.fail(w, r, action, &id, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, internal.ErrAPIKeyRevoked) {
+		h.fail(w, r, action, &id, http.StatusConflict, err.Error())
+		return
+	}
+	log.Printf("admin %s key %d failed: %v", action, id, err)
+	h.fail(w, r, action, &id, http.StatusInternalServerError, "internal error")
+}
+
+// respond пишет ответ и фиксирует действие в журнале администратора.
+func (h *Handler) respond(w http.ResponseWriter, r *http.Request, action string, keyID *int64, st int, out any) {
+	if out == nil {
+		w.WriteHeader(st)
+		h.record(r, action, keyID, st)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(st)
+
+	err := json.NewEncoder(w).Encode(out)
+	if err != nil {
+		log.Printf("encode response failed (path=%s status=%d): %v", r.URL.Path, st, err)
+	}
+	h.record(r, action, keyID, st)
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, action string, keyID *int64, st int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(st)
+
+	err := json.NewEncoder(w).Encode(msg)
+	if err != nil {
+		log.Printf("encode response failed (path=%s status=%d): %v", r.URL.Path, st, err)
+	}
+	h.record(r, action, keyID, st)
+}
+
+func (h *Handler) record(r *http.Request, action string, keyID *int64, st int) {
+	admin, _ := middleware.AdminFromContext(r.Context())
+	err := h.audit.InsertAdminAudit(r.Context(), internal.AdminAuditEntry{
+		Actor:  admin,
+		Action: action,
+		KeyID:  keyID,
+		Status: st,
+	})
+	if err != nil {
+		log.Printf("admin audit failed (action=%s status=%d): %v", action, st, err)
+	}
+}
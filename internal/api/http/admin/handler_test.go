@@ -0,0 +1,75 @@
+package admin_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service-currency/internal"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"service-currency/internal/api/http/admin"
+	"service-currency/internal/mock"
+)
+
+func TestCreateKey_ErrorStatus(t *testing.T) {
+	mockStore := mock.NewMockAPIKeyStore(t)
+	mockAudit := mock.NewMockAdminAuditStorage(t)
+	mockAudit.EXPECT().InsertAdminAudit(testifymock.Anything, testifymock.Anything).Return(nil)
+
+	mux := http.NewServeMux()
+	peppers := internal.Peppers{{Version: 1, Key: "pepper"}}
+	admin.New(internal.NewAPIKeyManager(mockStore, peppers), mockAudit).Register(mux)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/v1/keys", strings.NewReader(body)))
+		return w
+	}
+
+	w := create(`{"owner":"treasury","scopes":["admin"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown scope`)
+
+	mockStore.EXPECT().
+		Create(testifymock.Anything, testifymock.Anything, testifymock.Anything).
+		Return(internal.APIKey{}, errors.New("insert api_keys: connection refused")).
+		Once()
+
+	w = create(`{"owner":"treasury"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "\"internal error\"\n", w.Body.String())
+}
+
+func TestUnknownPlan(t *testing.T) {
+	mockStore := mock.NewMockAPIKeyStore(t)
+	mockAudit := mock.NewMockAdminAuditStorage(t)
+	mockAudit.EXPECT().InsertAdminAudit(testifymock.Anything, testifymock.Anything).Return(nil)
+
+	mux := http.NewServeMux()
+	peppers := internal.Peppers{{Version: 1, Key: "pepper"}}
+	plans := internal.LimitPlans{internal.DefaultPlan: {RatePerSecond: 10, Burst: 20}}
+	admin.New(internal.NewAPIKeyManager(mockStore, peppers).WithPlans(plans), mockAudit).Register(mux)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"create", http.MethodPost, "/admin/v1/keys", `{"owner":"treasury","plan":"gold"}`},
+		{"set plan", http.MethodPut, "/admin/v1/keys/7/plan", `{"plan":"gold"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `unknown plan \"gold\"`)
+		})
+	}
+}
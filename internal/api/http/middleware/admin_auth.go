@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type adminCtxKey struct{}
+
+// AdminAuth пускает к админским маршрутам по заголовку Authorization: Bearer <token>.
+// tokens — имя администратора -> токен; имя попадает в контекст и в журнал действий.
+func AdminAuth(tokens map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			token = strings.TrimSpace(token)
+			if !ok || token == "" {
+				writeErr(w, http.StatusUnauthorized, errors.New("missing bearer token"))
+				return
+			}
+
+			// сравниваем со всеми токенами, чтобы время ответа не зависело от того, какой совпал
+			admin := ""
+			for name, t := range tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+					admin = name
+				}
+			}
+			if admin == "" {
+				writeErr(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminCtxKey{}, admin)))
+		})
+	}
+}
+
+// AdminFromContext возвращает имя администратора, прошедшего AdminAuth.
+func AdminFromContext(ctx context.Context) (string, bool) {
+	admin, ok := ctx.Value(adminCtxKey{}).(string)
+	return admin, ok
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"service-currency/internal"
 	"strings"
@@ -17,17 +18,38 @@ func APIKeyAuth(store internal.APIKeyValidator) func(http.Handler) http.Handler
 				return
 			}
 
-			exists, active, err := store.Validate(r.Context(), key)
+			principal, status, err := store.Validate(r.Context(), key)
 			if err != nil {
 				writeErr(w, http.StatusInternalServerError, errors.New("internal error"))
 				return
 			}
-			if !exists {
+			switch status {
+			case internal.APIKeyActive:
+			case internal.APIKeyUnknown:
 				writeErr(w, http.StatusUnauthorized, errors.New("invalid api key"))
 				return
+			default:
+				writeErr(w, http.StatusForbidden, fmt.Errorf("api key is %s", status))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(internal.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireScope пропускает запрос, только если у ключа из контекста есть право scope.
+// Ставится на маршрут после APIKeyAuth.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := internal.PrincipalFromContext(r.Context())
+			if !ok {
+				writeErr(w, http.StatusUnauthorized, errors.New("missing X-API-Key"))
+				return
 			}
-			if !active {
-				writeErr(w, http.StatusForbidden, errors.New("api key is expired"))
+			if !principal.HasScope(scope) {
+				writeErr(w, http.StatusForbidden, fmt.Errorf("api key lacks scope %s", scope))
 				return
 			}
 
@@ -41,7 +63,7 @@ func writeErr(w http.ResponseWriter, status int, err error) {
 	w.Header().Set("Cache-Control", "no-store")
 	w.WriteHeader(status)
 
-	encError := json.NewEncoder(w).Encode(err)
+	encError := json.NewEncoder(w).Encode(err.Error())
 	if encError != nil {
 		http.Error(w, "Unknown error", http.StatusInternalServerError)
 	}
@@ -0,0 +1,32 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"service-currency/internal"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"service-currency/internal/api/http/middleware"
+)
+
+func TestRequireScope(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	h := middleware.RequireScope(internal.ScopeRatesHistorical)(next)
+
+	serve := func(p internal.Principal) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/rate/historical", nil)
+		r = r.WithContext(internal.WithPrincipal(r.Context(), p))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(internal.Principal{KeyID: 1, Scopes: []string{internal.ScopeRatesLatest}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "api key lacks scope rates:historical")
+
+	w = serve(internal.Principal{KeyID: 1, Scopes: []string{internal.ScopeRatesLatest, internal.ScopeRatesHistorical}})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"service-currency/internal"
+	"strconv"
+	"time"
+)
+
+// RateLimit списывает запрос с бакета и месячной квоты ключа. Ставится после APIKeyAuth.
+//
+// Заголовки ответа:
+//
+//	X-RateLimit-Limit, X-RateLimit-Remaining — ёмкость бакета и сколько в нём осталось
+//	X-RateLimit-Quota-Limit, X-RateLimit-Quota-Remaining — месячная квота и её остаток
+//	X-RateLimit-Quota-Reset — когда квота обнулится, unix-время
+//	Retry-After — при 429, через сколько секунд повторить
+//
+// Если лимитер недоступен, запрос пропускается: лимиты не должны ронять API.
+func RateLimit(limiter internal.RateLimiter, plans internal.LimitPlans) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := internal.PrincipalFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limits := plans.For(principal)
+			if limits.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			d, err := limiter.Allow(r.Context(), principal.KeyID, limits, time.Now())
+			if err != nil {
+				log.Printf("rate limit for key %d failed: %v", principal.KeyID, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			if limits.RatePerSecond > 0 {
+				h.Set("X-RateLimit-Limit", strconv.Itoa(limits.Burst))
+				h.Set("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			}
+			if limits.MonthlyQuota > 0 {
+				h.Set("X-RateLimit-Quota-Limit", strconv.FormatInt(limits.MonthlyQuota, 10))
+				h.Set("X-RateLimit-Quota-Remaining", strconv.FormatInt(max(limits.MonthlyQuota-d.QuotaUsed, 0), 10))
+				h.Set("X-RateLimit-Quota-Reset", strconv.FormatInt(d.QuotaReset.Unix(), 10))
+			}
+
+			if !d.Allowed {
+				h.Set("Retry-After", strconv.Itoa(int(math.Ceil(d.RetryAfter.Seconds()))))
+				msg := "rate limit exceeded"
+				if d.Exceeded == internal.LimitQuota {
+					msg = "monthly quota exceeded"
+				}
+				writeErr(w, http.StatusTooManyRequests, errors.New(msg))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
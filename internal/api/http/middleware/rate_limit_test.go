@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"service-currency/internal"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"service-currency/internal/api/http/middleware"
+)
+
+func TestRateLimit_Headers(t *testing.T) {
+	plans := internal.LimitPlans{internal.DefaultPlan: {RatePerSecond: 1, Burst: 1, MonthlyQuota: 100}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	h := middleware.RateLimit(internal.NewMemoryRateLimiter(), plans)(next)
+
+	serve := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/rate", nil)
+		r = r.WithContext(internal.WithPrincipal(r.Context(), internal.Principal{KeyID: 1, Plan: internal.DefaultPlan}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve()
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "99", w.Header().Get("X-RateLimit-Quota-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("X-RateLimit-Quota-Reset"))
+
+	w = serve()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, "99", w.Header().Get("X-RateLimit-Quota-Remaining"))
+	assert.Contains(t, w.Body.String(), "rate limit exceeded")
+}
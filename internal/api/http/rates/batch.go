@@ -0,0 +1,140 @@
+package rates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"service-currency/internal"
+)
+
+// maxBatchItems ограничивает размер пакета: все ноги читаются одним запросом.
+const maxBatchItems = 100
+
+// maxBatchDates ограничивает число разных дат в пакете: каждая дата, которой нет в БД, —
+// отдельный запрос к провайдеру, а лимит ключа списывает за пакет один запрос.
+const maxBatchDates = 10
+
+type batchRequest struct {
+	Items []batchItem `json:"items"`
+}
+
+type batchItem struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Date  string `json:"date,omitempty"`
+}
+
+type batchResult struct {
+	Index int `json:"index"`
+	*internal.PairRate
+	Error string `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+// getRatesBatch считает курсы для нескольких пар за один запрос. Ошибка по паре
+// попадает в её элемент ответа и не мешает остальным.
+func (h *Handler) getRatesBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req batchRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Items) == 0 {
+		h.fail(w, r, http.StatusBadRequest, "items are empty")
+		return
+	}
+	if len(req.Items) > maxBatchItems {
+		h.fail(w, r, http.StatusBadRequest, fmt.Sprintf("too many items, max %d", maxBatchItems))
+		return
+	}
+	for _, it := range req.Items {
+		if it.Date != "" {
+			if !h.requireScope(w, r, internal.ScopeRatesHistorical) {
+				return
+			}
+			break
+		}
+	}
+
+	out := batchResponse{Results: make([]batchResult, len(req.Items))}
+	rounding := make([]internal.Rounding, len(req.Items))
+
+	// в конвертер уходят только разобранные элементы; pos — их место в ответе
+	pairs := make([]internal.PairRequest, 0, len(req.Items))
+	pos := make([]int, 0, len(req.Items))
+	for i, it := range req.Items {
+		out.Results[i].Index = i
+
+		pair, err := parseBatchItem(it)
+		if err == nil {
+			rounding[i], err = internal.ParseRounding(r.URL.Query(), h.rounding.For(pair.Base, pair.Quote))
+		}
+		if err != nil {
+			out.Results[i].Error = err.Error()
+			continue
+		}
+		pairs = append(pairs, pair)
+		pos = append(pos, i)
+	}
+
+	dates := make(map[internal.Date]struct{})
+	for _, p := range pairs {
+		if p.Date != nil {
+			dates[*p.Date] = struct{}{}
+		}
+	}
+	if len(dates) > maxBatchDates {
+		h.fail(w, r, http.StatusBadRequest, fmt.Sprintf("too many distinct dates, max %d", maxBatchDates))
+		return
+	}
+
+	results, err := h.rates.GetPairRates(r.Context(), pairs)
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	for j, res := range results {
+		i := pos[j]
+		if res.Err != nil {
+			out.Results[i].Error = res.Err.Error()
+			continue
+		}
+		pr := res.Rate
+		pr.Rate = rounding[i].Apply(pr.Rate)
+		out.Results[i].PairRate = &pr
+	}
+
+	h.respond(w, r, out, nil)
+}
+
+func parseBatchItem(it batchItem) (internal.PairRequest, error) {
+	base, err := internal.NewCurrencyCode(it.Base)
+	if err != nil {
+		return internal.PairRequest{}, err
+	}
+	quote, err := internal.NewCurrencyCode(it.Quote)
+	if err != nil {
+		return internal.PairRequest{}, err
+	}
+
+	pair := internal.PairRequest{Base: base, Quote: quote}
+	if it.Date != "" {
+		date, err := parseDate(it.Date)
+		if err != nil {
+			return internal.PairRequest{}, err
+		}
+		pair.Date = &date
+	}
+	return pair, nil
+}
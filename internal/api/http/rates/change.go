@@ -0,0 +1,54 @@
+package rates
+
+import (
+	"net/http"
+
+	"service-currency/internal"
+)
+
+func (h *Handler) getChange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+
+	base, err := internal.NewCurrencyCode(q.Get("base"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, to, ok := h.parseRange(w, r)
+	if !ok {
+		return
+	}
+
+	symbols := make([]internal.CurrencyCode, 0, len(h.supportedCurrencies))
+	for _, ccy := range h.supportedCurrencies {
+		if ccy != base {
+			symbols = append(symbols, ccy)
+		}
+	}
+
+	out, err := h.rates.GetChange(r.Context(), base, symbols, from, to)
+	if err != nil {
+		h.fail(w, r, convertErrStatus(err), err.Error())
+		return
+	}
+
+	for quote, c := range out.Rates {
+		rounding, err := internal.ParseRounding(q, h.rounding.For(base, quote))
+		if err != nil {
+			h.fail(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.StartRate = rounding.Apply(c.StartRate)
+		c.EndRate = rounding.Apply(c.EndRate)
+		c.Change = rounding.Apply(c.Change)
+		out.Rates[quote] = c
+	}
+
+	h.respond(w, r, out, &out.To)
+}
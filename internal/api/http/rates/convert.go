@@ -0,0 +1,63 @@
+package rates
+
+import (
+	"net/http"
+
+	"service-currency/internal"
+
+	"github.com/shopspring/decimal"
+)
+
+func (h *Handler) convert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+
+	from, err := internal.NewCurrencyCode(q.Get("from"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	to, err := internal.NewCurrencyCode(q.Get("to"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	amountRaw := q.Get("amount")
+	if amountRaw == "" {
+		h.fail(w, r, http.StatusBadRequest, "amount parameter is required")
+		return
+	}
+	amount, err := decimal.NewFromString(amountRaw)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, "invalid amount, expected a decimal number")
+		return
+	}
+
+	var out internal.Conversion
+	if dateRaw := q.Get("date"); dateRaw != "" {
+		var date internal.Date
+		date, err = parseDate(dateRaw)
+		if err != nil {
+			h.fail(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !h.requireScope(w, r, internal.ScopeRatesHistorical) {
+			return
+		}
+		out, err = h.rates.ConvertOn(r.Context(), from, to, amount, date)
+	} else {
+		out, err = h.rates.Convert(r.Context(), from, to, amount)
+	}
+	if err != nil {
+		h.fail(w, r, convertErrStatus(err), err.Error())
+		return
+	}
+
+	h.respond(w, r, out, out.Date)
+}
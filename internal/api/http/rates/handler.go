@@ -8,41 +8,56 @@ import (
 	"time"
 
 	"service-currency/internal"
+	"service-currency/internal/api/http/middleware"
 )
 
 type Handler struct {
 	rates               *internal.RateConverter
+	historical          *internal.HistoricalRates
 	logger              internal.RequestAuditLogger
-	client              internal.RatesClient
 	supportedCurrencies []internal.CurrencyCode
+	rounding            internal.RoundingRules
 }
 
 func New(
 	rates *internal.RateConverter,
+	historical *internal.HistoricalRates,
 	logger internal.RequestAuditLogger,
-	client internal.RatesClient,
 	supportedCurrencies []internal.CurrencyCode,
+	rounding internal.RoundingRules,
 ) *Handler {
-	return &Handler{rates: rates, logger: logger, client: client, supportedCurrencies: supportedCurrencies}
+	return &Handler{
+		rates:               rates,
+		historical:          historical,
+		logger:              logger,
+		supportedCurrencies: supportedCurrencies,
+		rounding:            rounding,
+	}
 }
 
+// Register регистрирует маршруты; у каждого своё право ключа, см. internal.AllScopes.
+// Курс на дату может уйти к провайдеру, поэтому запросы с date на маршрутах текущих курсов
+// дополнительно требуют internal.ScopeRatesHistorical, см. requireScope.
 func (h *Handler) Register(mux *http.ServeMux) {
-	mux.HandleFunc("/api/v1/rate", h.getRate)
-	mux.HandleFunc("/api/v1/rate/historical", h.getHistoricalRates)
+	route := func(path, scope string, fn http.HandlerFunc) {
+		mux.Handle(path, middleware.RequireScope(scope)(fn))
+	}
+
+	route("/api/v1/rate", internal.ScopeRatesLatest, h.getRate)
+	route("/api/v1/rate/historical", internal.ScopeRatesHistorical, h.getHistoricalRates)
+	route("/api/v1/rate/series", internal.ScopeRatesHistorical, h.getSeries)
+	route("/api/v1/rate/stats", internal.ScopeRatesHistorical, h.getStats)
+	route("/api/v1/convert", internal.ScopeConvert, h.convert)
+	route("/api/v1/rates:batch", internal.ScopeRatesLatest, h.getRatesBatch)
+	route("/api/v1/rates/matrix", internal.ScopeRatesLatest, h.getMatrix)
+	route("/api/v1/rates/change", internal.ScopeRatesHistorical, h.getChange)
 }
 
 func (h *Handler) getRate(w http.ResponseWriter, r *http.Request) {
 	var err error
 
 	if r.Method != http.MethodGet {
-		st := http.StatusMethodNotAllowed
-
-		writeErr(w, st, "method not allowed")
-
-		err = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
-		if err != nil {
-			log.Printf("audit log failed (path=%s status=%d): %v", r.URL.Path, st, err)
-		}
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -51,55 +66,51 @@ func (h *Handler) getRate(w http.ResponseWriter, r *http.Request) {
 
 	base, err := internal.NewCurrencyCode(baseRaw)
 	if err != nil {
-		st := http.StatusBadRequest
-		writeErr(w, st, err.Error())
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	quote, err := internal.NewCurrencyCode(quoteRaw)
 	if err != nil {
-		st := http.StatusBadRequest
-		writeErr(w, st, err.Error())
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	var out internal.PairRate
-	out, err = h.rates.GetPairRate(r.Context(), base, quote)
+	rounding, err := internal.ParseRounding(r.URL.Query(), h.rounding.For(base, quote))
 	if err != nil {
-		st := http.StatusBadRequest
-		writeErr(w, st, err.Error())
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	out.Rate = out.Rate.Round(2)
-	st := http.StatusOK
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(st)
-
-	err = json.NewEncoder(w).Encode(out)
-	if err != nil {
-		log.Printf("encode response failed (path=%s status=%d): %v", r.URL.Path, st, err)
+	var out internal.PairRate
+	if dateRaw := r.URL.Query().Get("date"); dateRaw != "" {
+		var date internal.Date
+		date, err = parseDate(dateRaw)
+		if err != nil {
+			h.fail(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !h.requireScope(w, r, internal.ScopeRatesHistorical) {
+			return
+		}
+		out, err = h.rates.GetPairRateOn(r.Context(), base, quote, date)
+	} else {
+		out, err = h.rates.GetPairRate(r.Context(), base, quote)
 	}
-
-	err = h.logger.LogRequest(r.Context(), r.URL.Path, &st, out.Date)
 	if err != nil {
-		log.Printf("audit log failed (path=%s status=%d): %v", r.URL.Path, st, err)
+		h.fail(w, r, convertErrStatus(err), err.Error())
+		return
 	}
+
+	out.Rate = rounding.Apply(out.Rate)
+	h.respond(w, r, out, out.Date)
 }
 
 func (h *Handler) getHistoricalRates(w http.ResponseWriter, r *http.Request) {
 	var err error
 
 	if r.Method != http.MethodGet {
-		st := http.StatusMethodNotAllowed
-		writeErr(w, st, "method not allowed")
-		err = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
-		if err != nil {
-			log.Printf("audit log failed (path=%s status=%d): %v", r.URL.Path, st, err)
-		}
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -107,33 +118,24 @@ func (h *Handler) getHistoricalRates(w http.ResponseWriter, r *http.Request) {
 	baseRaw := r.URL.Query().Get("base")
 
 	if dateRaw == "" {
-		st := http.StatusBadRequest
-		writeErr(w, st, "date parameter is required")
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, "date parameter is required")
 		return
 	}
 
 	if baseRaw == "" {
-		st := http.StatusBadRequest
-		writeErr(w, st, "base parameter is required")
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, "base parameter is required")
 		return
 	}
 
-	parsedTime, err := time.Parse("2006-01-02", dateRaw)
+	date, err := parseDate(dateRaw)
 	if err != nil {
-		st := http.StatusBadRequest
-		writeErr(w, st, "invalid date format, expected YYYY-MM-DD")
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
-	date := internal.Date{Time: parsedTime}
 
 	base, err := internal.NewCurrencyCode(baseRaw)
 	if err != nil {
-		st := http.StatusBadRequest
-		writeErr(w, st, err.Error())
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -144,29 +146,71 @@ func (h *Handler) getHistoricalRates(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	historicalResp, err := h.client.HistoricalRates(r.Context(), date, base, symbols)
+	historicalResp, err := h.historical.Get(r.Context(), date, base, symbols)
 	if err != nil {
-		st := http.StatusBadRequest
-		writeErr(w, st, err.Error())
-		_ = h.logger.LogRequest(r.Context(), r.URL.Path, &st, nil)
+		h.fail(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	h.respond(w, r, historicalResp, &historicalResp.Date)
+}
+
+// respond пишет 200 с телом out и фиксирует запрос в журнале.
+func (h *Handler) respond(w http.ResponseWriter, r *http.Request, out any, dateAsOf *internal.Date) {
 	st := http.StatusOK
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(st)
 
-	err = json.NewEncoder(w).Encode(historicalResp)
+	err := json.NewEncoder(w).Encode(out)
 	if err != nil {
 		log.Printf("encode response failed (path=%s status=%d): %v", r.URL.Path, st, err)
 	}
 
-	err = h.logger.LogRequest(r.Context(), r.URL.Path, &st, &historicalResp.Date)
+	h.audit(r, st, dateAsOf)
+}
+
+// fail пишет ошибку и фиксирует запрос в журнале.
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, st int, msg string) {
+	writeErr(w, st, msg)
+	h.audit(r, st, nil)
+}
+
+// requireScope — проверка права, которое зависит от параметров запроса, а не от маршрута.
+func (h *Handler) requireScope(w http.ResponseWriter, r *http.Request, scope string) bool {
+	principal, _ := internal.PrincipalFromContext(r.Context())
+	if principal.HasScope(scope) {
+		return true
+	}
+	h.fail(w, r, http.StatusForbidden, "api key lacks scope "+scope)
+	return false
+}
+
+func (h *Handler) audit(r *http.Request, st int, dateAsOf *internal.Date) {
+	err := h.logger.LogRequest(r.Context(), r.URL.Path, &st, dateAsOf)
 	if err != nil {
 		log.Printf("audit log failed (path=%s status=%d): %v", r.URL.Path, st, err)
 	}
 }
 
+// convertErrStatus — код ответа на ошибку RateConverter.
+func convertErrStatus(err error) int {
+	if errors.Is(err, internal.ErrMixedDates) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+func parseDate(raw string) (internal.Date, error) {
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return internal.Date{}, errors.New("invalid date format, expected YYYY-MM-DD")
+	}
+	if t.After(time.Now().UTC()) {
+		return internal.Date{}, errors.New("date is in the future")
+	}
+	return internal.Date{Time: t}, nil
+}
+
 func writeErr(w http.ResponseWriter, status int, msg string) {
 	var err error
 
@@ -0,0 +1,76 @@
+package rates_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"service-currency/internal"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	rateshttp "service-currency/internal/api/http/rates"
+	"service-currency/internal/mock"
+)
+
+// Запрос на дату может уйти к провайдеру: ключу с правами по умолчанию он недоступен
+// и на маршрутах текущих курсов.
+func TestDatedRequestsRequireHistoricalScope(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockLogger := mock.NewMockRequestAuditLogger(t)
+	mockLogger.EXPECT().LogRequest(testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything).Return(nil)
+
+	mux := http.NewServeMux()
+	rateshttp.New(internal.NewRateConverter(mockStorage), nil, mockLogger, nil, internal.RoundingRules{}).Register(mux)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"rate", http.MethodGet, "/api/v1/rate?base=USD&quote=EUR&date=2025-01-10", ""},
+		{"convert", http.MethodGet, "/api/v1/convert?from=USD&to=EUR&amount=10&date=2025-01-10", ""},
+		{"matrix", http.MethodGet, "/api/v1/rates/matrix?currencies=USD,EUR&date=2025-01-10", ""},
+		{"batch", http.MethodPost, "/api/v1/rates:batch", `{"items":[{"base":"USD","quote":"EUR"},{"base":"USD","quote":"JPY","date":"2025-01-10"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			r = r.WithContext(internal.WithPrincipal(r.Context(), internal.Principal{KeyID: 1, Scopes: internal.DefaultScopes}))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), "api key lacks scope rates:historical")
+		})
+	}
+}
+
+// Каждая дата, которой нет в БД, — запрос к провайдеру, поэтому их число в пакете ограничено.
+func TestBatchLimitsDistinctDates(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockLogger := mock.NewMockRequestAuditLogger(t)
+	mockLogger.EXPECT().LogRequest(testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything).Return(nil)
+
+	mux := http.NewServeMux()
+	rateshttp.New(internal.NewRateConverter(mockStorage), nil, mockLogger, nil, internal.RoundingRules{}).Register(mux)
+
+	items := make([]string, 0, 11)
+	for day := 1; day <= 11; day++ {
+		items = append(items, fmt.Sprintf(`{"base":"USD","quote":"EUR","date":"2025-01-%02d"}`, day))
+	}
+	body := `{"items":[` + strings.Join(items, ",") + `]}`
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/rates:batch", strings.NewReader(body))
+	r = r.WithContext(internal.WithPrincipal(r.Context(), internal.Principal{KeyID: 1, Scopes: internal.AllScopes}))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "too many distinct dates, max 10")
+}
@@ -0,0 +1,78 @@
+package rates
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"service-currency/internal"
+)
+
+// maxMatrixCurrencies ограничивает размер матрицы: ячеек — квадрат числа валют.
+const maxMatrixCurrencies = 50
+
+func (h *Handler) getMatrix(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+
+	raw := q.Get("currencies")
+	if raw == "" {
+		h.fail(w, r, http.StatusBadRequest, "currencies parameter is required")
+		return
+	}
+	parts := strings.Split(raw, ",")
+	if len(parts) > maxMatrixCurrencies {
+		h.fail(w, r, http.StatusBadRequest, fmt.Sprintf("too many currencies, max %d", maxMatrixCurrencies))
+		return
+	}
+	currencies := make([]internal.CurrencyCode, 0, len(parts))
+	for _, p := range parts {
+		ccy, err := internal.NewCurrencyCode(p)
+		if err != nil {
+			h.fail(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		currencies = append(currencies, ccy)
+	}
+
+	var out internal.RateMatrix
+	var err error
+	if dateRaw := q.Get("date"); dateRaw != "" {
+		var date internal.Date
+		date, err = parseDate(dateRaw)
+		if err != nil {
+			h.fail(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !h.requireScope(w, r, internal.ScopeRatesHistorical) {
+			return
+		}
+		out, err = h.rates.GetMatrixOn(r.Context(), currencies, date)
+	} else {
+		out, err = h.rates.GetMatrix(r.Context(), currencies)
+	}
+	if err != nil {
+		h.fail(w, r, convertErrStatus(err), err.Error())
+		return
+	}
+
+	for base, row := range out.Rates {
+		for quote, rate := range row {
+			if base == quote {
+				continue
+			}
+			rounding, err := internal.ParseRounding(q, h.rounding.For(base, quote))
+			if err != nil {
+				h.fail(w, r, http.StatusBadRequest, err.Error())
+				return
+			}
+			row[quote] = rounding.Apply(rate)
+		}
+	}
+
+	h.respond(w, r, out, out.Date)
+}
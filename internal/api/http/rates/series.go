@@ -0,0 +1,96 @@
+package rates
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"service-currency/internal"
+)
+
+// maxSeriesDays ограничивает длину ряда: точка на каждый день диапазона.
+const maxSeriesDays = 3660
+
+func (h *Handler) getSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+
+	base, err := internal.NewCurrencyCode(q.Get("base"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quote, err := internal.NewCurrencyCode(q.Get("quote"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, to, ok := h.parseRange(w, r)
+	if !ok {
+		return
+	}
+
+	fill := internal.FillMode(strings.ToLower(q.Get("fill")))
+	if fill == "" {
+		fill = internal.FillSkip
+	}
+
+	rounding, err := internal.ParseRounding(q, h.rounding.For(base, quote))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.rates.GetSeries(r.Context(), base, quote, from, to, fill)
+	if err != nil {
+		h.fail(w, r, convertErrStatus(err), err.Error())
+		return
+	}
+
+	for i := range out.Points {
+		out.Points[i].Rate = rounding.Apply(out.Points[i].Rate)
+	}
+
+	var asOf *internal.Date
+	if n := len(out.Points); n > 0 {
+		asOf = &out.Points[n-1].Date
+	}
+	h.respond(w, r, out, asOf)
+}
+
+// parseRange читает обязательные from и to. При ошибке сам отвечает клиенту и возвращает ok=false.
+func (h *Handler) parseRange(w http.ResponseWriter, r *http.Request) (from, to internal.Date, ok bool) {
+	q := r.URL.Query()
+
+	if q.Get("from") == "" || q.Get("to") == "" {
+		h.fail(w, r, http.StatusBadRequest, "from and to parameters are required")
+		return internal.Date{}, internal.Date{}, false
+	}
+
+	from, err := parseDate(q.Get("from"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, "from: "+err.Error())
+		return internal.Date{}, internal.Date{}, false
+	}
+	to, err = parseDate(q.Get("to"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, "to: "+err.Error())
+		return internal.Date{}, internal.Date{}, false
+	}
+
+	if to.Before(from.Time) {
+		h.fail(w, r, http.StatusBadRequest, "from is after to")
+		return internal.Date{}, internal.Date{}, false
+	}
+	if to.Sub(from.Time).Hours()/24 >= maxSeriesDays {
+		h.fail(w, r, http.StatusBadRequest, fmt.Sprintf("range is too long, max %d days", maxSeriesDays))
+		return internal.Date{}, internal.Date{}, false
+	}
+	return from, to, true
+}
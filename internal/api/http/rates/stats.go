@@ -0,0 +1,53 @@
+package rates
+
+import (
+	"net/http"
+
+	"service-currency/internal"
+)
+
+func (h *Handler) getStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.fail(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+
+	base, err := internal.NewCurrencyCode(q.Get("base"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quote, err := internal.NewCurrencyCode(q.Get("quote"))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, to, ok := h.parseRange(w, r)
+	if !ok {
+		return
+	}
+
+	rounding, err := internal.ParseRounding(q, h.rounding.For(base, quote))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.rates.GetStats(r.Context(), base, quote, from, to)
+	if err != nil {
+		h.fail(w, r, convertErrStatus(err), err.Error())
+		return
+	}
+
+	// волатильность безразмерна — правило округления курса к ней не относится
+	out.Min = rounding.Apply(out.Min)
+	out.Max = rounding.Apply(out.Max)
+	out.Mean = rounding.Apply(out.Mean)
+	out.Median = rounding.Apply(out.Median)
+
+	h.respond(w, r, out, &out.To)
+}
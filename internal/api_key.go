@@ -3,38 +3,93 @@ package internal
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
 	"strings"
+	"time"
 )
 
 type APIKeyRepository interface {
-	GetStatusByHash(ctx context.Context, keyHash string) (exists bool, isActive bool, err error)
+	// GetByHash возвращает ключ по HMAC; found=false — такого ключа нет.
+	GetByHash(ctx context.Context, keyHash string) (key APIKey, found bool, err error)
+	// Rehash заменяет хеш oldHash ключа id хешем newHash версии секрета version.
+	// Если хеш ключа уже не oldHash (ключ успели повернуть), ничего не делает.
+	Rehash(ctx context.Context, id int64, oldHash, newHash string, version int) error
 }
 
+// APIKeyStatus — почему ключ пускают или не пускают.
+type APIKeyStatus string
+
+const (
+	APIKeyActive    APIKeyStatus = "active"
+	APIKeyUnknown   APIKeyStatus = "unknown"
+	APIKeyExpired   APIKeyStatus = "expired"
+	APIKeyRevoked   APIKeyStatus = "revoked"   // отозван навсегда
+	APIKeySuspended APIKeyStatus = "suspended" // выключен, можно включить снова
+)
+
 type defaultAPIKeyValidator struct { // приватная реализация
-	repo        APIKeyRepository
-	encodingKey string
+	repo    APIKeyRepository
+	peppers Peppers
+	usage   *APIKeyUsage
 }
 
 type APIKeyValidator interface {
-	Validate(ctx context.Context, rawKey string) (exists bool, isActive bool, err error)
+	// Validate проверяет ключ; principal заполнен, только если status == APIKeyActive.
+	Validate(ctx context.Context, rawKey string) (principal Principal, status APIKeyStatus, err error)
 }
 
-func NewAPIKeyValidator(repo APIKeyRepository, encodingKey string) APIKeyValidator {
+// NewAPIKeyValidator — usage может быть nil, тогда last_used_at не обновляется.
+func NewAPIKeyValidator(repo APIKeyRepository, peppers Peppers, usage *APIKeyUsage) APIKeyValidator {
 	return &defaultAPIKeyValidator{
-		repo:        repo,
-		encodingKey: strings.TrimSpace(encodingKey),
+		repo:    repo,
+		peppers: peppers,
+		usage:   usage,
 	}
 }
-func (v *defaultAPIKeyValidator) Validate(ctx context.Context, rawKey string) (exists bool, isActive bool, err error) {
+
+// Validate ищет ключ по хешу каждой версии секрета, начиная с текущей.
+// Активный ключ со старой версией перехешируется текущей.
+func (v *defaultAPIKeyValidator) Validate(ctx context.Context, rawKey string) (Principal, APIKeyStatus, error) {
 	rawKey = strings.TrimSpace(rawKey)
 	if rawKey == "" {
-		return false, false, nil
+		return Principal{}, APIKeyUnknown, nil
 	}
 
-	keyHash := hashKey(rawKey, v.encodingKey)
-	return v.repo.GetStatusByHash(ctx, keyHash)
+	for i, pepper := range v.peppers {
+		keyHash := hashKey(rawKey, pepper.Key)
+		key, found, err := v.repo.GetByHash(ctx, keyHash)
+		if err != nil {
+			return Principal{}, "", err
+		}
+		if !found || key.HashVersion != pepper.Version {
+			continue
+		}
+
+		now := time.Now()
+		status := key.Status(now)
+		if status != APIKeyActive {
+			return Principal{}, status, nil
+		}
+
+		if i > 0 {
+			current := v.peppers.Current()
+			err = v.repo.Rehash(ctx, key.ID, keyHash, hashKey(rawKey, current.Key), current.Version)
+			if err != nil {
+				// ключ проверен, попробуем перехешировать в следующий раз
+				log.Printf("rehash api key %d to encoding key version %d failed: %v", key.ID, current.Version, err)
+			}
+		}
+		if v.usage != nil {
+			v.usage.Touch(key.ID, now)
+		}
+		return key.Principal(), status, nil
+	}
+	return Principal{}, APIKeyUnknown, nil
 }
 
 func hashKey(rawKey, encodingKey string) string {
@@ -42,3 +97,181 @@ func hashKey(rawKey, encodingKey string) string {
 	_, _ = mac.Write([]byte(rawKey))
 	return hex.EncodeToString(mac.Sum(nil))
 }
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyRevoked  = errors.New("api key is revoked")
+	// ErrInvalidKeyParams — метаданные ключа не прошли проверку: ошибка клиента, а не хранилища.
+	ErrInvalidKeyParams = errors.New("invalid key parameters")
+)
+
+type APIKey struct {
+	ID          int64      `json:"id"`
+	IsActive    bool       `json:"is_active"`
+	Owner       string     `json:"owner"`
+	Description string     `json:"description"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// HashVersion — версия секрета (Pepper.Version), которой посчитан хеш ключа.
+	HashVersion int `json:"hash_version"`
+
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason string     `json:"revocation_reason,omitempty"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+
+	Scopes []string `json:"scopes"`
+	Plan   string   `json:"plan"`
+	// Limits — ограничения ключа вместо ограничений тарифа Plan.
+	Limits *Limits `json:"limits,omitempty"`
+}
+
+// Status — состояние ключа на момент now. Отзыв важнее истечения срока, истечение — выключения.
+func (k APIKey) Status(now time.Time) APIKeyStatus {
+	switch {
+	case k.RevokedAt != nil:
+		return APIKeyRevoked
+	case k.ExpiresAt != nil && !now.Before(*k.ExpiresAt):
+		return APIKeyExpired
+	case !k.IsActive:
+		return APIKeySuspended
+	}
+	return APIKeyActive
+}
+
+// APIKeyStore — управление ключами. В БД лежит только HMAC ключа.
+// Методы с id возвращают ErrAPIKeyNotFound, если ключа нет.
+type APIKeyStore interface {
+	// Create сохраняет ключ с метаданными из meta, хеш посчитан версией meta.HashVersion;
+	// ID, IsActive и CreatedAt назначает БД.
+	Create(ctx context.Context, keyHash string, meta APIKey) (APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	// SetActive выключает и включает ключ; включить отозванный нельзя — ErrAPIKeyRevoked.
+	SetActive(ctx context.Context, id int64, active bool) error
+	// Revoke отзывает ключ навсегда с причиной reason.
+	Revoke(ctx context.Context, id int64, reason string) error
+	// UpdateHash заменяет секрет ключа id; хеш посчитан версией секрета version.
+	UpdateHash(ctx context.Context, id int64, keyHash string, version int) error
+	// SetPlan задаёт тариф ключа и его собственные ограничения; limits nil — по тарифу.
+	SetPlan(ctx context.Context, id int64, plan string, limits *Limits) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// APIKeyManager выпускает ключи. Сам ключ возвращается один раз и нигде не хранится.
+type APIKeyManager struct {
+	store   APIKeyStore
+	peppers Peppers
+	plans   LimitPlans
+}
+
+// NewAPIKeyManager — ключи хешируются текущей версией из peppers.
+func NewAPIKeyManager(store APIKeyStore, peppers Peppers) *APIKeyManager {
+	return &APIKeyManager{store: store, peppers: peppers}
+}
+
+// WithPlans задаёт тарифы из конфига: ключ нельзя выпустить или перевести на тариф не из них.
+// Без WithPlans тариф не проверяется.
+func (m *APIKeyManager) WithPlans(plans LimitPlans) *APIKeyManager {
+	m.plans = plans
+	return m
+}
+
+func (m *APIKeyManager) checkPlan(plan string) error {
+	if m.plans == nil {
+		return nil
+	}
+	return m.plans.Check(plan)
+}
+
+// Create выпускает новый активный ключ с метаданными meta.
+// Без meta.Scopes ключ получает DefaultScopes.
+func (m *APIKeyManager) Create(ctx context.Context, meta APIKey) (rawKey string, key APIKey, err error) {
+	if meta.ExpiresAt != nil && !meta.ExpiresAt.After(time.Now()) {
+		return "", APIKey{}, fmt.Errorf("%w: expires_at is in the past", ErrInvalidKeyParams)
+	}
+	if len(meta.Scopes) == 0 {
+		meta.Scopes = DefaultScopes
+	}
+	if meta.Plan == "" {
+		meta.Plan = DefaultPlan
+	}
+	err = errors.Join(CheckScopes(meta.Scopes), m.checkPlan(meta.Plan), CheckLimits(meta.Limits))
+	if err != nil {
+		return "", APIKey{}, fmt.Errorf("%w: %w", ErrInvalidKeyParams, err)
+	}
+
+	rawKey, err = generateKey()
+	if err != nil {
+		return "", APIKey{}, err
+	}
+
+	pepper := m.peppers.Current()
+	meta.HashVersion = pepper.Version
+	key, err = m.store.Create(ctx, hashKey(rawKey, pepper.Key), meta)
+	if err != nil {
+		return "", APIKey{}, fmt.Errorf("create api key: %w", err)
+	}
+	return rawKey, key, nil
+}
+
+func (m *APIKeyManager) List(ctx context.Context) ([]APIKey, error) {
+	return m.store.List(ctx)
+}
+
+// SetPlan переводит ключ на тариф plan; limits — свои ограничения ключа, nil — как у тарифа.
+func (m *APIKeyManager) SetPlan(ctx context.Context, id int64, plan string, limits *Limits) error {
+	plan = strings.TrimSpace(plan)
+	if plan == "" {
+		return fmt.Errorf("%w: plan is required", ErrInvalidKeyParams)
+	}
+	err := errors.Join(m.checkPlan(plan), CheckLimits(limits))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidKeyParams, err)
+	}
+	return m.store.SetPlan(ctx, id, plan, limits)
+}
+
+// Revoke отзывает ключ навсегда: запросы с ним получают 403 "api key is revoked".
+func (m *APIKeyManager) Revoke(ctx context.Context, id int64, reason string) error {
+	return m.store.Revoke(ctx, id, strings.TrimSpace(reason))
+}
+
+// Suspend временно выключает ключ; Reactivate включает его обратно.
+func (m *APIKeyManager) Suspend(ctx context.Context, id int64) error {
+	return m.store.SetActive(ctx, id, false)
+}
+
+// Reactivate снова включает выключенный ключ.
+func (m *APIKeyManager) Reactivate(ctx context.Context, id int64) error {
+	return m.store.SetActive(ctx, id, true)
+}
+
+// Delete удаляет ключ совсем. Для временной блокировки — Suspend.
+func (m *APIKeyManager) Delete(ctx context.Context, id int64) error {
+	return m.store.Delete(ctx, id)
+}
+
+// Rotate выдаёт ключу id новый секрет; старый перестаёт действовать сразу.
+func (m *APIKeyManager) Rotate(ctx context.Context, id int64) (rawKey string, err error) {
+	rawKey, err = generateKey()
+	if err != nil {
+		return "", err
+	}
+
+	pepper := m.peppers.Current()
+	err = m.store.UpdateHash(ctx, id, hashKey(rawKey, pepper.Key), pepper.Version)
+	if err != nil {
+		return "", fmt.Errorf("rotate api key %d: %w", id, err)
+	}
+	return rawKey, nil
+}
+
+// generateKey — 32 случайных байта в hex.
+func generateKey() (string, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", fmt.Errorf("generate api key: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
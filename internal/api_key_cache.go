@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Пределы кеша: перебор случайных ключей не должен съесть память. Неизвестные ключи
+// ограничены отдельно, чтобы их поток не вытеснял настоящие.
+const (
+	maxCachedAPIKeys  = 10000
+	maxUnknownAPIKeys = 10000
+)
+
+// CachedAPIKeyRepository — APIKeyRepository с кешем в памяти. Найденные ключи живут ttl,
+// ненайденные — negativeTTL. Изменения ключей приходят через Invalidate, TTL — страховка,
+// если уведомление потерялось.
+type CachedAPIKeyRepository struct {
+	repo        APIKeyRepository
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]cachedAPIKey
+	unknown map[string]time.Time // хеш -> до какого момента считать ключ неизвестным
+}
+
+type cachedAPIKey struct {
+	key     APIKey
+	expires time.Time
+}
+
+func NewCachedAPIKeyRepository(repo APIKeyRepository, ttl, negativeTTL time.Duration) *CachedAPIKeyRepository {
+	return &CachedAPIKeyRepository{
+		repo:        repo,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		keys:        make(map[string]cachedAPIKey),
+		unknown:     make(map[string]time.Time),
+	}
+}
+
+// WithClock подменяет часы; для тестов.
+func (c *CachedAPIKeyRepository) WithClock(now func() time.Time) *CachedAPIKeyRepository {
+	c.now = now
+	return c
+}
+
+func (c *CachedAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (APIKey, bool, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	e, found := c.keys[keyHash]
+	unknownUntil, unknown := c.unknown[keyHash]
+	c.mu.Unlock()
+	if found && now.Before(e.expires) {
+		return e.key, true, nil
+	}
+	if unknown && now.Before(unknownUntil) {
+		return APIKey{}, false, nil
+	}
+
+	key, found, err := c.repo.GetByHash(ctx, keyHash)
+	if err != nil {
+		return APIKey{}, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if found {
+		delete(c.unknown, keyHash)
+		if c.ttl > 0 {
+			c.storeKey(keyHash, cachedAPIKey{key: key, expires: now.Add(c.ttl)}, now)
+		}
+		return key, true, nil
+	}
+
+	delete(c.keys, keyHash)
+	if c.negativeTTL > 0 {
+		c.storeUnknown(keyHash, now.Add(c.negativeTTL), now)
+	}
+	return APIKey{}, false, nil
+}
+
+// storeKey кеширует найденный ключ; вызывается под c.mu.
+func (c *CachedAPIKeyRepository) storeKey(keyHash string, e cachedAPIKey, now time.Time) {
+	if len(c.keys) >= maxCachedAPIKeys {
+		for h, old := range c.keys {
+			if !now.Before(old.expires) {
+				delete(c.keys, h)
+			}
+		}
+	}
+	if len(c.keys) >= maxCachedAPIKeys {
+		return
+	}
+	c.keys[keyHash] = e
+}
+
+// storeUnknown кеширует неизвестный хеш; вызывается под c.mu. Если места нет даже после
+// чистки просроченных, идёт перебор ключей — тогда проще начать с пустого кеша.
+func (c *CachedAPIKeyRepository) storeUnknown(keyHash string, until, now time.Time) {
+	if len(c.unknown) >= maxUnknownAPIKeys {
+		for h, old := range c.unknown {
+			if !now.Before(old) {
+				delete(c.unknown, h)
+			}
+		}
+	}
+	if len(c.unknown) >= maxUnknownAPIKeys {
+		clear(c.unknown)
+	}
+	c.unknown[keyHash] = until
+}
+
+// Rehash меняет хеш в хранилище и сразу забывает ключ, не дожидаясь уведомления.
+func (c *CachedAPIKeyRepository) Rehash(ctx context.Context, id int64, oldHash, newHash string, version int) error {
+	err := c.repo.Rehash(ctx, id, oldHash, newHash, version)
+
+	c.mu.Lock()
+	delete(c.keys, oldHash)
+	c.mu.Unlock()
+	c.Invalidate(id)
+	return err
+}
+
+// Invalidate выбрасывает из кеша ключ id: его отозвали, выключили или поменяли. Неизвестные
+// хеши сбрасываются целиком: среди них может оказаться новый хеш ключа — Validate сначала
+// пробует текущую версию секрета, а перехешировать ключ могла другая реплика.
+func (c *CachedAPIKeyRepository) Invalidate(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for h, e := range c.keys {
+		if e.key.ID == id {
+			delete(c.keys, h)
+		}
+	}
+	clear(c.unknown)
+}
+
+// InvalidateAll очищает кеш целиком, например после потери уведомлений.
+func (c *CachedAPIKeyRepository) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.keys)
+	clear(c.unknown)
+}
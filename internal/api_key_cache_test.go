@@ -0,0 +1,175 @@
+package internal_test
+
+import (
+	"context"
+	"fmt"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestCachedAPIKeyRepository(t *testing.T) {
+	mockRepo := mock.NewMockAPIKeyRepository(t)
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	cache := internal.NewCachedAPIKeyRepository(mockRepo, 30*time.Second, 5*time.Second).
+		WithClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	key := internal.APIKey{ID: 7, IsActive: true}
+	mockRepo.EXPECT().GetByHash(testifymock.Anything, "known").Return(key, true, nil).Times(2)
+	mockRepo.EXPECT().GetByHash(testifymock.Anything, "unknown").Return(internal.APIKey{}, false, nil).Times(2)
+
+	for range 3 {
+		got, found, err := cache.GetByHash(ctx, "known")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, key, got)
+
+		_, found, err = cache.GetByHash(ctx, "unknown")
+		require.NoError(t, err)
+		assert.False(t, found)
+	}
+
+	// неизвестный ключ помнится меньше
+	now = now.Add(10 * time.Second)
+	_, found, err := cache.GetByHash(ctx, "unknown")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// отзыв ключа сбрасывает его сразу
+	cache.Invalidate(7)
+	_, found, err = cache.GetByHash(ctx, "known")
+	require.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestCachedAPIKeyRepository_UnknownFloodKeepsKnownKeys(t *testing.T) {
+	mockRepo := mock.NewMockAPIKeyRepository(t)
+	cache := internal.NewCachedAPIKeyRepository(mockRepo, time.Minute, time.Minute)
+	ctx := context.Background()
+
+	key := internal.APIKey{ID: 7, IsActive: true}
+	mockRepo.EXPECT().GetByHash(testifymock.Anything, "known").Return(key, true, nil).Once()
+	mockRepo.EXPECT().
+		GetByHash(testifymock.Anything, testifymock.AnythingOfType("string")).
+		Return(internal.APIKey{}, false, nil)
+
+	// перебор случайных ключей, которых больше, чем помещается в кеш
+	for i := range 20001 {
+		_, found, err := cache.GetByHash(ctx, fmt.Sprintf("random-%d", i))
+		require.NoError(t, err)
+		require.False(t, found)
+		if i == 15000 {
+			_, found, err = cache.GetByHash(ctx, "known")
+			require.NoError(t, err)
+			require.True(t, found)
+		}
+	}
+
+	// настоящий ключ по-прежнему отдаётся из кеша: GetByHash("known") ожидался один раз
+	got, found, err := cache.GetByHash(ctx, "known")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, key, got)
+}
+
+func TestCachedAPIKeyRepository_RehashKeepsKeyValid(t *testing.T) {
+	mockStore := mock.NewMockAPIKeyStore(t)
+	mockRepo := mock.NewMockAPIKeyRepository(t)
+	ctx := context.Background()
+
+	// хранилище: хеш -> ключ
+	stored := make(map[string]internal.APIKey)
+	mockStore.EXPECT().
+		Create(testifymock.Anything, testifymock.AnythingOfType("string"), testifymock.Anything).
+		RunAndReturn(func(_ context.Context, keyHash string, meta internal.APIKey) (internal.APIKey, error) {
+			meta.ID, meta.IsActive = 5, true
+			stored[keyHash] = meta
+			return meta, nil
+		}).
+		Once()
+	mockRepo.EXPECT().
+		GetByHash(testifymock.Anything, testifymock.AnythingOfType("string")).
+		RunAndReturn(func(_ context.Context, keyHash string) (internal.APIKey, bool, error) {
+			key, ok := stored[keyHash]
+			return key, ok, nil
+		})
+	mockRepo.EXPECT().
+		Rehash(testifymock.Anything, int64(5), testifymock.AnythingOfType("string"), testifymock.AnythingOfType("string"), 2).
+		RunAndReturn(func(_ context.Context, _ int64, oldHash, newHash string, version int) error {
+			key := stored[oldHash]
+			key.HashVersion = version
+			delete(stored, oldHash)
+			stored[newHash] = key
+			return nil
+		}).
+		Once()
+
+	// ключ выпущен до смены секрета
+	raw, _, err := internal.NewAPIKeyManager(mockStore, testPeppers).Create(ctx, internal.APIKey{Owner: "treasury"})
+	require.NoError(t, err)
+
+	peppers, err := internal.NewPeppers(internal.Pepper{Version: 1, Key: "pepper"}, internal.Pepper{Version: 2, Key: "new-pepper"})
+	require.NoError(t, err)
+	cache := internal.NewCachedAPIKeyRepository(mockRepo, time.Minute, time.Minute)
+	validator := internal.NewAPIKeyValidator(cache, peppers, nil)
+
+	for i := range 3 {
+		principal, status, err := validator.Validate(ctx, raw)
+		require.NoError(t, err)
+		require.Equal(t, internal.APIKeyActive, status, "call %d", i)
+		assert.Equal(t, int64(5), principal.KeyID)
+	}
+}
+
+func TestCachedAPIKeyRepository_RehashOnAnotherReplica(t *testing.T) {
+	mockRepo := mock.NewMockAPIKeyRepository(t)
+	ctx := context.Background()
+
+	stored := map[string]internal.APIKey{"old-hash": {ID: 5, IsActive: true, HashVersion: 1}}
+	mockRepo.EXPECT().
+		GetByHash(testifymock.Anything, testifymock.AnythingOfType("string")).
+		RunAndReturn(func(_ context.Context, keyHash string) (internal.APIKey, bool, error) {
+			key, ok := stored[keyHash]
+			return key, ok, nil
+		})
+	mockRepo.EXPECT().
+		Rehash(testifymock.Anything, int64(5), "old-hash", "new-hash", 2).
+		RunAndReturn(func(_ context.Context, _ int64, oldHash, newHash string, version int) error {
+			key := stored[oldHash]
+			key.HashVersion = version
+			delete(stored, oldHash)
+			stored[newHash] = key
+			return nil
+		}).
+		Once()
+
+	replicaA := internal.NewCachedAPIKeyRepository(mockRepo, time.Minute, time.Minute)
+	replicaB := internal.NewCachedAPIKeyRepository(mockRepo, time.Minute, time.Minute)
+
+	// B видел ключ только под старым хешем, новый у него среди неизвестных
+	_, found, err := replicaB.GetByHash(ctx, "new-hash")
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = replicaB.GetByHash(ctx, "old-hash")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// A перехешировал ключ, B узнаёт об этом из уведомления
+	require.NoError(t, replicaA.Rehash(ctx, 5, "old-hash", "new-hash", 2))
+	replicaB.Invalidate(5)
+
+	_, found, err = replicaB.GetByHash(ctx, "old-hash")
+	require.NoError(t, err)
+	assert.False(t, found)
+	key, found, err := replicaB.GetByHash(ctx, "new-hash")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 2, key.HashVersion)
+}
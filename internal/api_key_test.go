@@ -0,0 +1,170 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+var testPeppers = internal.Peppers{{Version: 1, Key: "pepper"}}
+
+func TestAPIKeyManager_Create_StoresOnlyHash(t *testing.T) {
+	mockStore := mock.NewMockAPIKeyStore(t)
+	mockRepo := mock.NewMockAPIKeyRepository(t)
+
+	var stored string
+	mockStore.EXPECT().
+		Create(testifymock.Anything, testifymock.AnythingOfType("string"), internal.APIKey{Owner: "treasury", Scopes: internal.DefaultScopes, Plan: internal.DefaultPlan, HashVersion: 1}).
+		Run(func(_ context.Context, keyHash string, _ internal.APIKey) { stored = keyHash }).
+		Return(internal.APIKey{ID: 7, IsActive: true, Owner: "treasury", HashVersion: 1}, nil).
+		Once()
+
+	raw, key, err := internal.NewAPIKeyManager(mockStore, testPeppers).Create(context.Background(), internal.APIKey{Owner: "treasury"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), key.ID)
+	assert.Len(t, raw, 64)
+	assert.NotEqual(t, raw, stored)
+
+	// выданный ключ проходит проверку по сохранённому хешу
+	mockRepo.EXPECT().
+		GetByHash(testifymock.Anything, stored).
+		Return(key, true, nil).
+		Once()
+
+	principal, status, err := internal.NewAPIKeyValidator(mockRepo, testPeppers, nil).Validate(context.Background(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, internal.APIKeyActive, status)
+	assert.Equal(t, int64(7), principal.KeyID)
+	assert.Equal(t, "treasury", principal.Owner)
+}
+
+func TestAPIKeyManager_Create_RejectsUnknownScope(t *testing.T) {
+	mockStore := mock.NewMockAPIKeyStore(t)
+
+	_, _, err := internal.NewAPIKeyManager(mockStore, testPeppers).
+		Create(context.Background(), internal.APIKey{Owner: "treasury", Scopes: []string{"rates:latest", "admin"}})
+
+	require.ErrorIs(t, err, internal.ErrInvalidKeyParams)
+	require.EqualError(t, err, `invalid key parameters: unknown scope "admin"`)
+}
+
+func TestAPIKey_Status(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		key  internal.APIKey
+		want internal.APIKeyStatus
+	}{
+		{"active", internal.APIKey{IsActive: true, ExpiresAt: &future}, internal.APIKeyActive},
+		{"expired", internal.APIKey{IsActive: true, ExpiresAt: &past}, internal.APIKeyExpired},
+		{"suspended", internal.APIKey{IsActive: false}, internal.APIKeySuspended},
+		{"revoked wins over expiry", internal.APIKey{RevokedAt: &past, ExpiresAt: &past}, internal.APIKeyRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.key.Status(now))
+		})
+	}
+}
+
+func TestAPIKeyValidator_TouchesUsageInMemory(t *testing.T) {
+	mockRepo := mock.NewMockAPIKeyRepository(t)
+	mockUsage := mock.NewMockAPIKeyUsageWriter(t)
+
+	mockRepo.EXPECT().
+		GetByHash(testifymock.Anything, testifymock.AnythingOfType("string")).
+		Return(internal.APIKey{ID: 3, IsActive: true, HashVersion: 1}, true, nil).
+		Times(2)
+
+	usage := internal.NewAPIKeyUsage(mockUsage)
+	validator := internal.NewAPIKeyValidator(mockRepo, testPeppers, usage)
+	for range 2 {
+		_, status, err := validator.Validate(context.Background(), "some-key")
+		require.NoError(t, err)
+		require.Equal(t, internal.APIKeyActive, status)
+	}
+
+	// две проверки — одна запись при сбросе
+	mockUsage.EXPECT().
+		TouchLastUsed(testifymock.Anything, testifymock.MatchedBy(func(m map[int64]time.Time) bool {
+			_, ok := m[3]
+			return len(m) == 1 && ok
+		})).
+		Return(nil).
+		Once()
+
+	require.NoError(t, usage.Flush(context.Background()))
+	require.NoError(t, usage.Flush(context.Background()))
+}
+
+func TestAPIKeyValidator_RehashesWithCurrentPepper(t *testing.T) {
+	mockStore := mock.NewMockAPIKeyStore(t)
+	mockRepo := mock.NewMockAPIKeyRepository(t)
+
+	// ключ выпущен до смены секрета
+	var oldHash string
+	mockStore.EXPECT().
+		Create(testifymock.Anything, testifymock.AnythingOfType("string"), testifymock.Anything).
+		Run(func(_ context.Context, keyHash string, _ internal.APIKey) { oldHash = keyHash }).
+		Return(internal.APIKey{ID: 5, IsActive: true, HashVersion: 1}, nil).
+		Once()
+	raw, key, err := internal.NewAPIKeyManager(mockStore, testPeppers).Create(context.Background(), internal.APIKey{Owner: "treasury"})
+	require.NoError(t, err)
+
+	peppers, err := internal.NewPeppers(internal.Pepper{Version: 1, Key: "pepper"}, internal.Pepper{Version: 2, Key: "new-pepper"})
+	require.NoError(t, err)
+
+	var newHash string
+	mockRepo.EXPECT().
+		GetByHash(testifymock.Anything, testifymock.AnythingOfType("string")).
+		RunAndReturn(func(_ context.Context, keyHash string) (internal.APIKey, bool, error) {
+			if keyHash != oldHash {
+				newHash = keyHash
+				return internal.APIKey{}, false, nil
+			}
+			return key, true, nil
+		}).
+		Twice()
+	mockRepo.EXPECT().
+		Rehash(testifymock.Anything, int64(5), oldHash, testifymock.AnythingOfType("string"), 2).
+		Run(func(_ context.Context, _ int64, _, h string, _ int) { assert.Equal(t, newHash, h) }).
+		Return(nil).
+		Once()
+
+	principal, status, err := internal.NewAPIKeyValidator(mockRepo, peppers, nil).Validate(context.Background(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, internal.APIKeyActive, status)
+	assert.Equal(t, int64(5), principal.KeyID)
+}
+
+func TestNewPeppers(t *testing.T) {
+	peppers, err := internal.NewPeppers(internal.Pepper{Version: 1, Key: "old"}, internal.Pepper{Version: 3, Key: "new"})
+	require.NoError(t, err)
+	assert.Equal(t, internal.Pepper{Version: 3, Key: "new"}, peppers.Current())
+
+	_, err = internal.NewPeppers(internal.Pepper{Version: 1, Key: "a"}, internal.Pepper{Version: 1, Key: "b"})
+	require.EqualError(t, err, "duplicate encoding key version 1")
+}
+
+func TestAPIKeyManager_Rotate_NotFound(t *testing.T) {
+	mockStore := mock.NewMockAPIKeyStore(t)
+
+	mockStore.EXPECT().
+		UpdateHash(testifymock.Anything, int64(42), testifymock.AnythingOfType("string"), 1).
+		Return(internal.ErrAPIKeyNotFound).
+		Once()
+
+	_, err := internal.NewAPIKeyManager(mockStore, testPeppers).Rotate(context.Background(), 42)
+
+	require.ErrorIs(t, err, internal.ErrAPIKeyNotFound)
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// APIKeyUsageWriter сохраняет время последнего использования ключей одним запросом.
+type APIKeyUsageWriter interface {
+	TouchLastUsed(ctx context.Context, lastUsed map[int64]time.Time) error
+}
+
+// APIKeyUsage копит last_used_at в памяти и сбрасывает в БД раз в интервал,
+// чтобы проверка ключа не добавляла запись в БД к каждому запросу.
+type APIKeyUsage struct {
+	writer APIKeyUsageWriter
+
+	mu      sync.Mutex
+	pending map[int64]time.Time
+}
+
+func NewAPIKeyUsage(writer APIKeyUsageWriter) *APIKeyUsage {
+	return &APIKeyUsage{writer: writer, pending: make(map[int64]time.Time)}
+}
+
+// Touch отмечает, что ключ id использован в момент at.
+func (u *APIKeyUsage) Touch(id int64, at time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if cur, ok := u.pending[id]; !ok || at.After(cur) {
+		u.pending[id] = at
+	}
+}
+
+// Flush сохраняет накопленное. При ошибке данные возвращаются в буфер до следующей попытки.
+func (u *APIKeyUsage) Flush(ctx context.Context) error {
+	u.mu.Lock()
+	batch := u.pending
+	u.pending = make(map[int64]time.Time, len(batch))
+	u.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := u.writer.TouchLastUsed(ctx, batch)
+	if err != nil {
+		for id, at := range batch {
+			u.Touch(id, at)
+		}
+		return err
+	}
+	return nil
+}
+
+// Run сбрасывает накопленное каждые interval и последний раз — при остановке ctx.
+func (u *APIKeyUsage) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx уже отменён — на финальный сброс даём отдельное время
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := u.Flush(flushCtx)
+			if err != nil {
+				log.Printf("flush api key usage failed: %v", err)
+			}
+			return nil
+		case <-t.C:
+			err := u.Flush(ctx)
+			if err != nil {
+				log.Printf("flush api key usage failed: %v", err)
+			}
+		}
+	}
+}
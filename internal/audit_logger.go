@@ -11,7 +11,8 @@ type RequestAuditLogger interface {
 }
 
 type AuditLogStorage interface {
-	Insert(ctx context.Context, path string, status *int, dateAsOf *Date) error
+	// Insert пишет запрос в журнал; keyID == nil — запрос без ключа.
+	Insert(ctx context.Context, path string, status *int, dateAsOf *Date, keyID *int64) error
 }
 
 func NewStorageAuditLogger(storage AuditLogStorage) *StorageAuditLogger {
@@ -29,7 +30,12 @@ func (l *StorageAuditLogger) LogRequest(ctx context.Context, endpoint string, st
 		p = "unknown"
 	}
 
-	err := l.auditLogStorage.Insert(ctx, p, status, dateAsOf)
+	var keyID *int64
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		keyID = &principal.KeyID
+	}
+
+	err := l.auditLogStorage.Insert(ctx, p, status, dateAsOf, keyID)
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
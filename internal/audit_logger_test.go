@@ -0,0 +1,28 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestStorageAuditLogger_LogRequest_AttributesToPrincipal(t *testing.T) {
+	mockStorage := mock.NewMockAuditLogStorage(t)
+
+	status := 200
+	keyID := int64(5)
+	mockStorage.EXPECT().
+		Insert(testifymock.Anything, "api/v1/rate", &status, (*internal.Date)(nil), &keyID).
+		Return(nil).
+		Once()
+
+	ctx := internal.WithPrincipal(context.Background(), internal.Principal{KeyID: 5, Owner: "treasury"})
+	err := internal.NewStorageAuditLogger(mockStorage).LogRequest(ctx, "/api/v1/rate", &status, nil)
+
+	require.NoError(t, err)
+}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultBackfillWorkers — сколько дней запрашивать у провайдера одновременно.
+const defaultBackfillWorkers = 4
+
+// Backfill загружает историю курсов за диапазон дат. Повторный запуск продолжает
+// прерванный: дни, на которые в БД уже есть все symbols, провайдер не запрашивает.
+type Backfill struct {
+	storage Storage
+	writer  RatesWriter
+	client  RatesClient
+	workers int
+}
+
+func NewBackfill(storage Storage, writer RatesWriter, client RatesClient) *Backfill {
+	return &Backfill{storage: storage, writer: writer, client: client, workers: defaultBackfillWorkers}
+}
+
+// WithWorkers задаёт число одновременных запросов к провайдеру.
+func (b *Backfill) WithWorkers(n int) *Backfill {
+	if n > 0 {
+		b.workers = n
+	}
+	return b
+}
+
+type BackfillReport struct {
+	Fetched []Date // дни, загруженные у провайдера
+	Skipped int    // дни, которые уже были в БД целиком
+	Failed  map[Date]error
+}
+
+// Run загружает курсы base->symbols за каждый день [from, to]. Ошибка по дню не останавливает
+// остальные — она попадает в отчёт; Run возвращает ошибку, только если не удалось прочитать БД
+// или отменён ctx.
+func (b *Backfill) Run(ctx context.Context, base CurrencyCode, symbols []CurrencyCode, from, to Date) (BackfillReport, error) {
+	if from.IsZero() || to.IsZero() {
+		return BackfillReport{}, errors.New("date range is empty")
+	}
+	if to.Before(from.Time) {
+		return BackfillReport{}, fmt.Errorf("invalid range: %s is after %s", from.Format(dateLayout), to.Format(dateLayout))
+	}
+	if len(symbols) == 0 {
+		return BackfillReport{}, errors.New("symbols are empty")
+	}
+
+	missing, err := missingSymbols(ctx, b.storage, base, symbols, from, to)
+	if err != nil {
+		return BackfillReport{}, err
+	}
+
+	report := BackfillReport{Failed: map[Date]error{}}
+	var dates []Date
+	for d := from; !d.After(to.Time); d = nextDay(d) {
+		if len(missing[d.Format(dateLayout)]) == 0 {
+			report.Skipped++
+			continue
+		}
+		dates = append(dates, d)
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(b.workers)
+	for _, d := range dates {
+		g.Go(func() error {
+			err := b.fetchDay(gctx, base, d, missing[d.Format(dateLayout)])
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				report.Failed[d] = err
+				log.Printf("backfill %s @%s failed: %v", base, d.Format(dateLayout), err)
+				return nil
+			}
+			report.Fetched = append(report.Fetched, d)
+			return nil
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// missingSymbols возвращает для каждого дня диапазона symbols, которых нет в БД. Одним запросом GetRange.
+func missingSymbols(ctx context.Context, storage Storage, base CurrencyCode, symbols []CurrencyCode, from, to Date) (map[string][]CurrencyCode, error) {
+	rows, err := storage.GetRange(ctx, base, symbols, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get stored range %s: %w", base, err)
+	}
+
+	stored := make(map[string]map[CurrencyCode]struct{})
+	for _, r := range rows {
+		if r.AsOfDate == nil {
+			continue
+		}
+		key := r.AsOfDate.Format(dateLayout)
+		if stored[key] == nil {
+			stored[key] = make(map[CurrencyCode]struct{})
+		}
+		stored[key][r.QuoteCCY] = struct{}{}
+	}
+
+	out := make(map[string][]CurrencyCode)
+	for d := from; !d.After(to.Time); d = nextDay(d) {
+		key := d.Format(dateLayout)
+		for _, s := range symbols {
+			if _, ok := stored[key][s]; !ok && s != base {
+				out[key] = append(out[key], s)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (b *Backfill) fetchDay(ctx context.Context, base CurrencyCode, date Date, symbols []CurrencyCode) error {
+	resp, err := b.client.HistoricalRates(ctx, date, base, symbols)
+	if err != nil {
+		return fmt.Errorf("historical rates: %w", err)
+	}
+
+	respBase, rates, err := resp.ParseRates()
+	if err != nil {
+		return err
+	}
+	if respBase != base {
+		return fmt.Errorf("provider returned base %s, requested %s", respBase, base)
+	}
+	if !resp.Date.Equal(date.Time) {
+		return fmt.Errorf("%w: requested %s, got %s", ErrDateMismatch, date.Format(dateLayout), resp.Date.Format(dateLayout))
+	}
+
+	err = b.writer.UpsertRatesMap(ctx, respBase, date, rates)
+	if err != nil {
+		return fmt.Errorf("save rates: %w", err)
+	}
+	return nil
+}
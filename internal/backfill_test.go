@@ -0,0 +1,86 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"service-currency/internal"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestBackfill_Run_SkipsStoredDaysAndFetchesMissingSymbols(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+
+	symbols := []internal.CurrencyCode{internal.USD, internal.EUR}
+	d1, d2 := seriesDay(1), seriesDay(2)
+
+	// 1-е сохранено целиком, на 2-е есть только USD, 3-го нет вовсе
+	mockStorage.EXPECT().
+		GetRange(testifymock.Anything, internal.RUB, symbols, seriesDay(1), seriesDay(3)).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &d1},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &d1},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &d2},
+		}, nil).
+		Once()
+
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, seriesDay(2), internal.RUB, []internal.CurrencyCode{internal.EUR}).
+		Return(&internal.LatestRatesResponse{Date: seriesDay(2), Base: "RUB", Rates: map[string]string{"EUR": "0.0096"}}, nil).
+		Once()
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, seriesDay(3), internal.RUB, symbols).
+		Return(nil, errors.New("upstream down")).
+		Once()
+
+	mockWriter.EXPECT().
+		UpsertRatesMap(testifymock.Anything, internal.RUB, seriesDay(2), map[internal.CurrencyCode]decimal.Decimal{
+			internal.EUR: decimal.RequireFromString("0.0096"),
+		}).
+		Return(nil).
+		Once()
+
+	report, err := internal.NewBackfill(mockStorage, mockWriter, mockClient).
+		WithWorkers(2).
+		Run(context.Background(), internal.RUB, symbols, seriesDay(1), seriesDay(3))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, []internal.Date{seriesDay(2)}, report.Fetched)
+	require.Len(t, report.Failed, 1)
+	assert.Contains(t, report.Failed[seriesDay(3)].Error(), "upstream down")
+}
+
+func TestBackfill_Run_RejectsAnotherDate(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+
+	symbols := []internal.CurrencyCode{internal.USD}
+
+	mockStorage.EXPECT().
+		GetRange(testifymock.Anything, internal.RUB, symbols, seriesDay(4), seriesDay(4)).
+		Return(nil, nil).
+		Once()
+	// на выходной провайдер отвечает курсом предыдущего рабочего дня
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, seriesDay(4), internal.RUB, symbols).
+		Return(&internal.LatestRatesResponse{Date: seriesDay(3), Base: "RUB", Rates: map[string]string{"USD": "0.01"}}, nil).
+		Once()
+
+	report, err := internal.NewBackfill(mockStorage, mockWriter, mockClient).
+		Run(context.Background(), internal.RUB, symbols, seriesDay(4), seriesDay(4))
+
+	require.NoError(t, err)
+	assert.Empty(t, report.Fetched)
+	require.Len(t, report.Failed, 1)
+	assert.ErrorIs(t, report.Failed[seriesDay(4)], internal.ErrDateMismatch)
+}
@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PairRequest — одна пара в пакетном запросе. Date == nil — последний курс.
+type PairRequest struct {
+	Base  CurrencyCode
+	Quote CurrencyCode
+	Date  *Date
+}
+
+// PairResult — результат по одной паре: либо Rate, либо Err.
+type PairResult struct {
+	Rate PairRate
+	Err  error
+}
+
+// GetPairRates считает курсы для набора пар по тем же правилам, что GetPairRate и GetPairRateOn.
+// В режиме ConversionPivot все нужные ноги читаются из БД одним запросом. Ошибка по паре,
+// в том числе от провайдера на её дату, не роняет остальные; общая ошибка возвращается,
+// только если не удалось прочитать хранилище.
+func (s *RateConverter) GetPairRates(ctx context.Context, reqs []PairRequest) ([]PairResult, error) {
+	out := make([]PairResult, len(reqs))
+
+	// поиск пути строит граф по всем парам — по одной ноге его не собрать
+	if s.mode == ConversionPath {
+		for i, req := range reqs {
+			if req.Date == nil {
+				out[i].Rate, out[i].Err = s.GetPairRate(ctx, req.Base, req.Quote)
+			} else {
+				out[i].Rate, out[i].Err = s.GetPairRateOn(ctx, req.Base, req.Quote, *req.Date)
+			}
+		}
+		return out, nil
+	}
+
+	var legs []RateLeg
+	seen := make(map[legKey]struct{})
+	for i, req := range reqs {
+		out[i].Err = checkPair(req.Base, req.Quote)
+		if out[i].Err == nil && req.Date != nil && req.Date.IsZero() {
+			out[i].Err = errors.New("date is empty")
+		}
+		if out[i].Err != nil {
+			continue
+		}
+
+		for _, q := range s.legQuotes(req.Base, req.Quote) {
+			k := newLegKey(q, req.Date)
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				legs = append(legs, RateLeg{Quote: q, Date: req.Date})
+			}
+		}
+	}
+
+	idx := newLegIndex()
+	var failed map[legKey]error
+	if len(legs) > 0 {
+		rows, err := s.storage.GetLegs(ctx, s.pivot, legs)
+		if err != nil {
+			return nil, fmt.Errorf("get legs %s: %w", s.pivot, err)
+		}
+		idx.add(rows)
+
+		failed = s.fillMissingFromHistory(ctx, legs, idx)
+	}
+
+	for i, req := range reqs {
+		if out[i].Err != nil {
+			continue
+		}
+		if req.Date != nil {
+			for _, q := range s.legQuotes(req.Base, req.Quote) {
+				if err, ok := failed[newLegKey(q, req.Date)]; ok {
+					out[i].Err = err
+					break
+				}
+			}
+			if out[i].Err != nil {
+				continue
+			}
+		}
+		out[i].Rate, out[i].Err = s.pairRate(ctx, req.Base, req.Quote, func(ctx context.Context, quotes []CurrencyCode) ([]CurrencyLatestRate, error) {
+			rows := idx.get(quotes, req.Date)
+			// общую дату ищем отдельным запросом только там, где последние курсы ног разошлись
+			if req.Date == nil && s.mixedDates == MixedDatesCommon && len(rows) > 1 && !sameLegDates(rows) {
+				return s.getLatestLegs(ctx, quotes)
+			}
+			return rows, nil
+		})
+	}
+	return out, nil
+}
+
+// fillMissingFromHistory догружает от провайдера ноги на даты, которых нет в БД.
+// Ноги, которые не удалось догрузить, возвращаются с ошибкой: она достаётся только парам на эту дату.
+func (s *RateConverter) fillMissingFromHistory(ctx context.Context, legs []RateLeg, idx *legIndex) map[legKey]error {
+	if s.history == nil {
+		return nil
+	}
+
+	missing := make(map[Date][]CurrencyCode)
+	var dates []Date
+	for _, l := range legs {
+		if l.Date == nil || idx.has(l.Quote, l.Date) {
+			continue
+		}
+		if _, ok := missing[*l.Date]; !ok {
+			dates = append(dates, *l.Date)
+		}
+		missing[*l.Date] = append(missing[*l.Date], l.Quote)
+	}
+
+	failed := make(map[legKey]error)
+	for _, d := range dates {
+		rows, err := s.getLegsOn(ctx, missing[d], d)
+		if err != nil {
+			for _, q := range missing[d] {
+				failed[newLegKey(q, &d)] = err
+			}
+			continue
+		}
+		idx.add(rows)
+	}
+	return failed
+}
+
+type legKey struct {
+	quote CurrencyCode
+	date  string // пусто — последний курс
+}
+
+func newLegKey(q CurrencyCode, d *Date) legKey {
+	if d == nil {
+		return legKey{quote: q}
+	}
+	return legKey{quote: q, date: d.Format(dateLayout)}
+}
+
+// legIndex раскладывает строки GetLegs по запрошенным ногам. Последний курс по quote —
+// строка с самой поздней датой: строки на даты не бывают новее последней.
+type legIndex struct {
+	latest map[CurrencyCode]CurrencyLatestRate
+	dated  map[legKey]CurrencyLatestRate
+}
+
+func newLegIndex() *legIndex {
+	return &legIndex{latest: map[CurrencyCode]CurrencyLatestRate{}, dated: map[legKey]CurrencyLatestRate{}}
+}
+
+func (x *legIndex) add(rows []CurrencyLatestRate) {
+	for _, r := range rows {
+		if r.AsOfDate == nil {
+			continue
+		}
+		x.dated[newLegKey(r.QuoteCCY, r.AsOfDate)] = r
+		if cur, ok := x.latest[r.QuoteCCY]; !ok || r.AsOfDate.After(cur.AsOfDate.Time) {
+			x.latest[r.QuoteCCY] = r
+		}
+	}
+}
+
+func (x *legIndex) has(q CurrencyCode, d *Date) bool {
+	_, ok := x.dated[newLegKey(q, d)]
+	return ok
+}
+
+func (x *legIndex) get(quotes []CurrencyCode, d *Date) []CurrencyLatestRate {
+	out := make([]CurrencyLatestRate, 0, len(quotes))
+	for _, q := range quotes {
+		var r CurrencyLatestRate
+		var ok bool
+		if d == nil {
+			r, ok = x.latest[q]
+		} else {
+			r, ok = x.dated[newLegKey(q, d)]
+		}
+		if ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func sameLegDates(rows []CurrencyLatestRate) bool {
+	dates := make([]*Date, len(rows))
+	for i := range rows {
+		dates[i] = rows[i].AsOfDate
+	}
+	return sameDates(dates)
+}
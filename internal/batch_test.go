@@ -0,0 +1,104 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestRateConverter_GetPairRates_OneQueryForAllLegs(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	today := internal.Date{Time: time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)}
+	past := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.EXPECT().
+		GetLegs(testifymock.Anything, internal.RUB, []internal.RateLeg{
+			{Quote: internal.USD},
+			{Quote: internal.EUR},
+			{Quote: internal.USD, Date: &past},
+		}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &today},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &today},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.0125"), AsOfDate: &past},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	results, err := converter.GetPairRates(context.Background(), []internal.PairRequest{
+		{Base: internal.USD, Quote: internal.EUR},
+		{Base: internal.RUB, Quote: internal.USD},
+		{Base: internal.USD, Quote: internal.RUB, Date: &past},
+		{Base: internal.CurrencyCode("XXX"), Quote: internal.USD},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "0.9500", results[0].Rate.Rate.StringFixed(4))
+
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, "0.0100", results[1].Rate.Rate.StringFixed(4))
+	assert.Equal(t, today, *results[1].Rate.Date)
+
+	require.NoError(t, results[2].Err)
+	assert.Equal(t, "80.0000", results[2].Rate.Rate.StringFixed(4))
+	assert.Equal(t, past, *results[2].Rate.Date)
+
+	require.Error(t, results[3].Err)
+	assert.Contains(t, results[3].Err.Error(), "unsupported currency")
+}
+
+func TestRateConverter_GetPairRates_HistoryErrorStaysOnItsDate(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+
+	stored := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+	broken := internal.Date{Time: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.EXPECT().
+		GetLegs(testifymock.Anything, internal.RUB, []internal.RateLeg{
+			{Quote: internal.USD, Date: &stored},
+			{Quote: internal.USD, Date: &broken},
+		}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.0125"), AsOfDate: &stored},
+		}, nil).
+		Once()
+	mockStorage.EXPECT().
+		GetOnDate(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD}, broken).
+		Return(nil, nil).
+		Once()
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, broken, internal.RUB, []internal.CurrencyCode{internal.USD}).
+		Return(nil, errors.New("provider is down")).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage).
+		WithHistory(internal.NewHistoricalRates(mockStorage, mockWriter, mockClient))
+	results, err := converter.GetPairRates(context.Background(), []internal.PairRequest{
+		{Base: internal.USD, Quote: internal.RUB, Date: &stored},
+		{Base: internal.USD, Quote: internal.RUB, Date: &broken},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "80.0000", results[0].Rate.Rate.StringFixed(4))
+
+	require.Error(t, results[1].Err)
+	assert.Contains(t, results[1].Err.Error(), "provider is down")
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// changePctPlaces — знаков после запятой в процентном изменении.
+const changePctPlaces = 4
+
+type RateChange struct {
+	StartDate *Date           `json:"start_date"`
+	StartRate decimal.Decimal `json:"start_rate"`
+	EndDate   *Date           `json:"end_date"`
+	EndRate   decimal.Decimal `json:"end_rate"`
+	Change    decimal.Decimal `json:"change"`
+	ChangePct decimal.Decimal `json:"change_pct"`
+}
+
+type RatesChange struct {
+	Base  CurrencyCode                `json:"base"`
+	From  Date                        `json:"from"`
+	To    Date                        `json:"to"`
+	Rates map[CurrencyCode]RateChange `json:"rates"`
+	// Missing — валюты, по которым в истории нет курса на from или на to.
+	Missing []CurrencyCode `json:"missing,omitempty"`
+}
+
+// GetChange считает, как изменился курс base->symbol для каждой валюты symbols между from и to.
+// Курс на дату — последний сохранённый не позже неё и не раньше чем за seriesLookback дней,
+// так что from и to могут приходиться на выходные. Из истории читаются только эти два окна.
+func (s *RateConverter) GetChange(ctx context.Context, base CurrencyCode, symbols []CurrencyCode, from, to Date) (RatesChange, error) {
+	if from.IsZero() || to.IsZero() {
+		return RatesChange{}, errors.New("date range is empty")
+	}
+	if to.Before(from.Time) {
+		return RatesChange{}, fmt.Errorf("invalid range: %s is after %s", from.Format(dateLayout), to.Format(dateLayout))
+	}
+	if !base.IsSupported() {
+		return RatesChange{}, errors.New("unsupported currency")
+	}
+
+	var quotes []CurrencyCode
+	seen := make(map[CurrencyCode]struct{})
+	for _, sym := range symbols {
+		err := checkPair(base, sym)
+		if err != nil {
+			return RatesChange{}, fmt.Errorf("%s: %w", sym, err)
+		}
+		for _, q := range s.legQuotes(base, sym) {
+			if _, ok := seen[q]; !ok {
+				seen[q] = struct{}{}
+				quotes = append(quotes, q)
+			}
+		}
+	}
+
+	out := RatesChange{Base: base, From: from, To: to, Rates: make(map[CurrencyCode]RateChange, len(symbols))}
+
+	var startRows, endRows []CurrencyLatestRate
+	if len(quotes) > 0 {
+		var err error
+		startRows, err = s.lookbackRange(ctx, quotes, from)
+		if err != nil {
+			return RatesChange{}, err
+		}
+		endRows = startRows
+		if !to.Equal(from.Time) {
+			endRows, err = s.lookbackRange(ctx, quotes, to)
+			if err != nil {
+				return RatesChange{}, err
+			}
+		}
+	}
+	startLegs, endLegs := lastOnOrBefore(startRows, from), lastOnOrBefore(endRows, to)
+
+	for _, sym := range symbols {
+		start, err := s.pairRate(ctx, base, sym, startLegs)
+		if err == nil && start.Rate.IsZero() {
+			err = errors.New("start rate is zero")
+		}
+		var end PairRate
+		if err == nil {
+			end, err = s.pairRate(ctx, base, sym, endLegs)
+		}
+		if err != nil {
+			if errors.Is(err, ErrMixedDates) {
+				return RatesChange{}, fmt.Errorf("%s/%s: %w", base, sym, err)
+			}
+			out.Missing = append(out.Missing, sym)
+			continue
+		}
+
+		change := end.Rate.Sub(start.Rate)
+		out.Rates[sym] = RateChange{
+			StartDate: start.Date,
+			StartRate: start.Rate,
+			EndDate:   end.Date,
+			EndRate:   end.Rate,
+			Change:    change,
+			ChangePct: change.Mul(decimal.NewFromInt(100)).DivRound(start.Rate, changePctPlaces),
+		}
+	}
+	return out, nil
+}
+
+// lookbackRange читает курсы за seriesLookback дней до date включительно.
+func (s *RateConverter) lookbackRange(ctx context.Context, quotes []CurrencyCode, date Date) ([]CurrencyLatestRate, error) {
+	rows, err := s.storage.GetRange(ctx, s.pivot, quotes, Date{Time: date.AddDate(0, 0, -seriesLookback)}, date)
+	if err != nil {
+		return nil, fmt.Errorf("get range %s/%s: %w", s.pivot, joinCodes(quotes), err)
+	}
+	return rows, nil
+}
+
+// lastOnOrBefore отдаёт ноги с последним курсом не позже date; rows упорядочены по quote и дате.
+func lastOnOrBefore(rows []CurrencyLatestRate, date Date) legsFunc {
+	last := make(map[CurrencyCode]CurrencyLatestRate)
+	for _, r := range rows {
+		if r.AsOfDate == nil || r.AsOfDate.After(date.Time) {
+			continue
+		}
+		if cur, ok := last[r.QuoteCCY]; !ok || !r.AsOfDate.Before(cur.AsOfDate.Time) {
+			last[r.QuoteCCY] = r
+		}
+	}
+
+	return func(_ context.Context, quotes []CurrencyCode) ([]CurrencyLatestRate, error) {
+		out := make([]CurrencyLatestRate, 0, len(quotes))
+		for _, q := range quotes {
+			if r, ok := last[q]; ok {
+				out = append(out, r)
+			}
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,75 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestRateConverter_GetChange(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	// seriesRows: EUR 0.0095 -> 0.009 (на 3-е), USD 0.01 на 1-е и 4-е; JPY нет вовсе
+	quotes := []internal.CurrencyCode{internal.EUR, internal.USD, internal.JPY}
+	for _, d := range []internal.Date{seriesDay(2), seriesDay(5)} {
+		mockStorage.EXPECT().
+			GetRange(testifymock.Anything, internal.RUB, quotes, internal.Date{Time: d.AddDate(0, 0, -10)}, d).
+			Return(rowsInRange(seriesRows(), internal.Date{Time: d.AddDate(0, 0, -10)}, d), nil).
+			Once()
+	}
+
+	converter := internal.NewRateConverter(mockStorage)
+	c, err := converter.GetChange(context.Background(), internal.RUB,
+		[]internal.CurrencyCode{internal.EUR, internal.USD, internal.JPY}, seriesDay(2), seriesDay(5))
+
+	require.NoError(t, err)
+	assert.Equal(t, []internal.CurrencyCode{internal.JPY}, c.Missing)
+
+	eur := c.Rates[internal.EUR]
+	assert.Equal(t, seriesDay(1), *eur.StartDate)
+	assert.Equal(t, seriesDay(3), *eur.EndDate)
+	assert.Equal(t, "-0.0005", eur.Change.String())
+	assert.Equal(t, "-5.2632", eur.ChangePct.String())
+
+	usd := c.Rates[internal.USD]
+	assert.Equal(t, seriesDay(4), *usd.EndDate)
+	assert.True(t, usd.Change.IsZero())
+}
+
+// Курс старше seriesLookback дней не подставляется ни в начало, ни в конец.
+func TestRateConverter_GetChange_EndOutsideLookback(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	quotes := []internal.CurrencyCode{internal.EUR}
+	for _, d := range []internal.Date{seriesDay(2), seriesDay(20)} {
+		from := internal.Date{Time: d.AddDate(0, 0, -10)}
+		mockStorage.EXPECT().
+			GetRange(testifymock.Anything, internal.RUB, quotes, from, d).
+			Return(rowsInRange(seriesRows(), from, d), nil).
+			Once()
+	}
+
+	converter := internal.NewRateConverter(mockStorage)
+	c, err := converter.GetChange(context.Background(), internal.RUB, quotes, seriesDay(2), seriesDay(20))
+
+	require.NoError(t, err)
+	assert.Empty(t, c.Rates)
+	assert.Equal(t, []internal.CurrencyCode{internal.EUR}, c.Missing)
+}
+
+// rowsInRange — строки за [from, to], как их вернул бы GetRange.
+func rowsInRange(rows []internal.CurrencyLatestRate, from, to internal.Date) []internal.CurrencyLatestRate {
+	var out []internal.CurrencyLatestRate
+	for _, r := range rows {
+		if !r.AsOfDate.Before(from.Time) && !r.AsOfDate.After(to.Time) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+type Conversion struct {
+	From   CurrencyCode    `json:"from"`
+	To     CurrencyCode    `json:"to"`
+	Amount decimal.Decimal `json:"amount"`
+	Result decimal.Decimal `json:"result"`
+	Rate   decimal.Decimal `json:"rate"`
+	Date   *Date           `json:"date,omitempty"`
+}
+
+// Convert пересчитывает amount из from в to по последнему курсу.
+// Результат округляется до минорных единиц to (для JPY — до целых, для BHD — до трёх знаков).
+func (s *RateConverter) Convert(ctx context.Context, from, to CurrencyCode, amount decimal.Decimal) (Conversion, error) {
+	if amount.IsNegative() {
+		return Conversion{}, errNegativeAmount
+	}
+
+	pr, err := s.GetPairRate(ctx, from, to)
+	if err != nil {
+		return Conversion{}, err
+	}
+	return convert(pr, amount), nil
+}
+
+// ConvertOn — то же, что Convert, но по курсу на дату date.
+func (s *RateConverter) ConvertOn(ctx context.Context, from, to CurrencyCode, amount decimal.Decimal, date Date) (Conversion, error) {
+	if amount.IsNegative() {
+		return Conversion{}, errNegativeAmount
+	}
+
+	pr, err := s.GetPairRateOn(ctx, from, to, date)
+	if err != nil {
+		return Conversion{}, err
+	}
+	return convert(pr, amount), nil
+}
+
+var errNegativeAmount = errors.New("amount must not be negative")
+
+func convert(pr PairRate, amount decimal.Decimal) Conversion {
+	result := amount.Mul(pr.Rate)
+	// у драгметаллов и расчётных единиц минорных единиц нет — такие суммы не округляем
+	if info, ok := pr.Quote.Info(); ok && info.MinorUnits != MinorUnitsNA {
+		result = result.Round(int32(info.MinorUnits))
+	}
+
+	return Conversion{
+		From:   pr.Base,
+		To:     pr.Quote,
+		Amount: amount,
+		Result: result,
+		Rate:   pr.Rate,
+		Date:   pr.Date,
+	}
+}
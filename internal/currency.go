@@ -23,15 +23,17 @@ const (
 	JPY CurrencyCode = "JPY"
 )
 
-var supportedSet = map[CurrencyCode]struct{}{
-	RUB: {}, USD: {}, EUR: {}, JPY: {},
-}
-
+// IsSupported сообщает, есть ли валюта в реестре ISO 4217 и включена ли она (см. EnableCurrencies).
 func (c *CurrencyCode) IsSupported() bool {
-	_, ok := supportedSet[*c]
-	return ok
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registry.isEnabled(*c)
 }
 
+// Info возвращает запись реестра ISO 4217 для валюты.
+func (c *CurrencyCode) Info() (CurrencyInfo, bool) { return LookupCurrency(c.String()) }
+
 func (c *CurrencyCode) String() string { return string(*c) }
 
 func (c *CurrencyCode) MarshalJSON() ([]byte, error) {
@@ -131,25 +131,9 @@ func (c *Client) FetchAndSaveLatest(
 		return nil, fmt.Errorf("latest rates: %w", err)
 	}
 
-	baseCCY, err := internal.NewCurrencyCode(resp.Base)
+	baseCCY, typedRates, err := resp.ParseRates()
 	if err != nil {
-		return nil, fmt.Errorf("invalid base %q: %w", resp.Base, err)
-	}
-
-	typedRates := make(map[internal.CurrencyCode]decimal.Decimal, len(resp.Rates))
-	for quoteStr, rateStr := range resp.Rates {
-		quote, err := internal.NewCurrencyCode(quoteStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid quote %q: %w", quoteStr, err)
-		}
-
-		rateStr = strings.TrimSpace(rateStr)
-		rate, err := decimal.NewFromString(rateStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid rate %s/%s=%q: %w", baseCCY, quote, rateStr, err)
-		}
-
-		typedRates[quote] = rate
+		return nil, err
 	}
 
 	if err := storage.UpsertRatesMap(reqCtx, baseCCY, resp.Date, typedRates); err != nil {
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// iso4217.csv — выгрузка ISO 4217 (List One): code,numeric,minor_units,name.
+//
+//go:embed iso4217.csv
+var iso4217CSV string
+
+// MinorUnitsNA — значение CurrencyInfo.MinorUnits для единиц без дробной части
+// в смысле ISO 4217 (драгметаллы, расчётные единицы).
+const MinorUnitsNA = -1
+
+type CurrencyInfo struct {
+	Code       CurrencyCode `json:"code"`
+	Numeric    int          `json:"numeric"`
+	Name       string       `json:"name"`
+	MinorUnits int          `json:"minor_units"`
+}
+
+// NumericCode возвращает числовой код в виде из стандарта, с ведущими нулями ("008").
+func (i CurrencyInfo) NumericCode() string { return fmt.Sprintf("%03d", i.Numeric) }
+
+type currencyRegistry struct {
+	mu      sync.RWMutex
+	all     map[CurrencyCode]CurrencyInfo
+	enabled map[CurrencyCode]struct{} // nil — включён весь реестр
+}
+
+var registry = mustLoadRegistry(iso4217CSV)
+
+func mustLoadRegistry(raw string) *currencyRegistry {
+	all, err := parseISO4217(raw)
+	if err != nil {
+		panic(fmt.Sprintf("load iso4217 registry: %v", err))
+	}
+	return &currencyRegistry{all: all}
+}
+
+func parseISO4217(raw string) (map[CurrencyCode]CurrencyInfo, error) {
+	records, err := csv.NewReader(strings.NewReader(raw)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, errors.New("registry is empty")
+	}
+
+	out := make(map[CurrencyCode]CurrencyInfo, len(records)-1)
+	for i, rec := range records[1:] {
+		if len(rec) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 fields, got %d", i+2, len(rec))
+		}
+
+		code := CurrencyCode(strings.TrimSpace(rec[0]))
+		if len(code) != 3 {
+			return nil, fmt.Errorf("line %d: bad code %q", i+2, rec[0])
+		}
+
+		numeric, err := strconv.Atoi(strings.TrimSpace(rec[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad numeric code %q: %w", i+2, rec[1], err)
+		}
+
+		minor := MinorUnitsNA
+		if m := strings.TrimSpace(rec[2]); m != "N.A." {
+			minor, err = strconv.Atoi(m)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad minor units %q: %w", i+2, rec[2], err)
+			}
+		}
+
+		if _, dup := out[code]; dup {
+			return nil, fmt.Errorf("line %d: duplicate code %s", i+2, code)
+		}
+		out[code] = CurrencyInfo{Code: code, Numeric: numeric, Name: strings.TrimSpace(rec[3]), MinorUnits: minor}
+	}
+	return out, nil
+}
+
+// LookupCurrency ищет валюту в полном реестре ISO 4217, независимо от того, включена ли она.
+func LookupCurrency(s string) (CurrencyInfo, bool) {
+	code := CurrencyCode(strings.ToUpper(strings.TrimSpace(s)))
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	info, ok := registry.all[code]
+	return info, ok
+}
+
+// EnableCurrencies ограничивает набор валют, которые принимает NewCurrencyCode.
+// Пустой список снова включает весь реестр.
+func EnableCurrencies(codes ...string) error {
+	if len(codes) == 0 {
+		registry.mu.Lock()
+		registry.enabled = nil
+		registry.mu.Unlock()
+		return nil
+	}
+
+	enabled := make(map[CurrencyCode]struct{}, len(codes))
+	for _, s := range codes {
+		info, ok := LookupCurrency(s)
+		if !ok {
+			return fmt.Errorf("unknown currency %q", s)
+		}
+		enabled[info.Code] = struct{}{}
+	}
+
+	registry.mu.Lock()
+	registry.enabled = enabled
+	registry.mu.Unlock()
+	return nil
+}
+
+// EnabledCurrencies возвращает включённые валюты, отсортированные по коду.
+func EnabledCurrencies() []CurrencyInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	out := make([]CurrencyInfo, 0, len(registry.all))
+	for code, info := range registry.all {
+		if registry.isEnabled(code) {
+			out = append(out, info)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+func (r *currencyRegistry) isEnabled(code CurrencyCode) bool {
+	if _, ok := r.all[code]; !ok {
+		return false
+	}
+	if r.enabled == nil {
+		return true
+	}
+	_, ok := r.enabled[code]
+	return ok
+}
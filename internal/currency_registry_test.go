@@ -0,0 +1,66 @@
+package internal_test
+
+import (
+	"service-currency/internal"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCurrency(t *testing.T) {
+	info, ok := internal.LookupCurrency(" jpy ")
+
+	require.True(t, ok)
+	assert.Equal(t, internal.JPY, info.Code)
+	assert.Equal(t, 392, info.Numeric)
+	assert.Equal(t, "Yen", info.Name)
+	assert.Equal(t, 0, info.MinorUnits)
+
+	info, ok = internal.LookupCurrency("ALL")
+	require.True(t, ok)
+	assert.Equal(t, "008", info.NumericCode())
+
+	info, ok = internal.LookupCurrency("XAU")
+	require.True(t, ok)
+	assert.Equal(t, internal.MinorUnitsNA, info.MinorUnits)
+
+	_, ok = internal.LookupCurrency("XXX")
+	assert.False(t, ok)
+}
+
+func TestNewCurrencyCode_FullRegistry(t *testing.T) {
+	ccy, err := internal.NewCurrencyCode("gbp")
+
+	require.NoError(t, err)
+	assert.Equal(t, internal.CurrencyCode("GBP"), ccy)
+
+	_, err = internal.NewCurrencyCode("BTC")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported currency")
+}
+
+func TestEnableCurrencies(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, internal.EnableCurrencies()) })
+
+	require.NoError(t, internal.EnableCurrencies("rub", "USD"))
+
+	_, err := internal.NewCurrencyCode("USD")
+	require.NoError(t, err)
+
+	_, err = internal.NewCurrencyCode("EUR")
+	require.Error(t, err)
+
+	enabled := internal.EnabledCurrencies()
+	require.Len(t, enabled, 2)
+	assert.Equal(t, internal.RUB, enabled[0].Code)
+	assert.Equal(t, internal.USD, enabled[1].Code)
+
+	err = internal.EnableCurrencies("USD", "ABC")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown currency")
+
+	// неудачный вызов не меняет текущий набор
+	_, err = internal.NewCurrencyCode("RUB")
+	require.NoError(t, err)
+}
@@ -48,3 +48,31 @@ func (d *Date) MarshalJSON() ([]byte, error) {
 	}
 	return []byte(fmt.Sprintf("%q", d.Time.Format(dateLayout))), nil
 }
+
+// sameDates сообщает, что все даты известны и совпадают.
+func sameDates(dates []*Date) bool {
+	for _, d := range dates {
+		if d == nil || !d.Equal(dates[0].Time) {
+			return false
+		}
+	}
+	return true
+}
+
+// oldestDate возвращает самую раннюю из известных дат или nil.
+func oldestDate(dates []*Date) *Date {
+	var out *Date
+	for _, d := range dates {
+		if d != nil && (out == nil || d.Before(out.Time)) {
+			out = d
+		}
+	}
+	return out
+}
+
+func formatDate(d *Date) string {
+	if d == nil || d.IsZero() {
+		return "unknown"
+	}
+	return d.Format(dateLayout)
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// RatesWriter сохраняет курсы на дату. Совпадает с currencyFreaks.RatesStorage,
+// так что обе роли закрывает одно хранилище.
+type RatesWriter interface {
+	UpsertRatesMap(ctx context.Context, base CurrencyCode, asOfDate Date, rates map[CurrencyCode]decimal.Decimal) error
+}
+
+// ErrDateMismatch — провайдер вернул курсы не на запрошенную дату, например на ближайший
+// рабочий день. Такие курсы не сохраняются: под своей датой они не закроют запрошенную.
+var ErrDateMismatch = errors.New("provider returned rates on another date")
+
+// HistoricalRates отдаёт курсы на прошедшую дату: сначала из БД, а при промахе
+// запрашивает у провайдера недостающие symbols и сохраняет их, чтобы следующий запрос обошёлся без него.
+type HistoricalRates struct {
+	storage Storage
+	writer  RatesWriter
+	client  RatesClient
+}
+
+func NewHistoricalRates(storage Storage, writer RatesWriter, client RatesClient) *HistoricalRates {
+	return &HistoricalRates{storage: storage, writer: writer, client: client}
+}
+
+func (h *HistoricalRates) Get(ctx context.Context, date Date, base CurrencyCode, symbols []CurrencyCode) (*LatestRatesResponse, error) {
+	if date.IsZero() {
+		return nil, errors.New("date is empty")
+	}
+
+	stored, err := h.storage.GetOnDate(ctx, base, symbols, date)
+	if err != nil {
+		return nil, fmt.Errorf("get stored rates %s @%s: %w", base, date.Format(dateLayout), err)
+	}
+
+	out := &LatestRatesResponse{Date: date, Base: base.String(), Rates: make(map[string]string, len(symbols))}
+	for _, r := range stored {
+		out.Rates[r.QuoteCCY.String()] = r.Rate.String()
+	}
+
+	missing := make([]CurrencyCode, 0, len(symbols))
+	for _, s := range symbols {
+		if _, ok := out.Rates[s.String()]; !ok && s != base {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) == 0 && (len(symbols) > 0 || len(stored) > 0) {
+		return out, nil
+	}
+
+	resp, err := h.client.HistoricalRates(ctx, date, base, missing)
+	if err != nil {
+		return nil, fmt.Errorf("historical rates: %w", err)
+	}
+
+	respBase, fetched, err := resp.ParseRates()
+	if err != nil {
+		return nil, err
+	}
+	if respBase != base {
+		return nil, fmt.Errorf("provider returned base %s, requested %s", respBase, base)
+	}
+	if !resp.Date.Equal(date.Time) {
+		return nil, fmt.Errorf("%w: requested %s, got %s", ErrDateMismatch, date.Format(dateLayout), resp.Date.Format(dateLayout))
+	}
+
+	err = h.writer.UpsertRatesMap(ctx, respBase, date, fetched)
+	if err != nil {
+		return nil, fmt.Errorf("save rates: %w", err)
+	}
+
+	for quote, rate := range fetched {
+		out.Rates[quote.String()] = rate.String()
+	}
+	return out, nil
+}
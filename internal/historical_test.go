@@ -0,0 +1,104 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestHistoricalRates_Get_FromStorage(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+
+	date := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+	symbols := []internal.CurrencyCode{internal.USD, internal.EUR}
+
+	mockStorage.EXPECT().
+		GetOnDate(testifymock.Anything, internal.RUB, symbols, date).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.0103"), AsOfDate: &date},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &date},
+		}, nil).
+		Once()
+
+	svc := internal.NewHistoricalRates(mockStorage, mockWriter, mockClient)
+	result, err := svc.Get(context.Background(), date, internal.RUB, symbols)
+
+	require.NoError(t, err)
+	assert.Equal(t, "RUB", result.Base)
+	assert.Equal(t, "0.0103", result.Rates["USD"])
+	assert.Equal(t, "0.0095", result.Rates["EUR"])
+}
+
+func TestHistoricalRates_Get_MissFetchesAndSaves(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+
+	date := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+	symbols := []internal.CurrencyCode{internal.USD, internal.EUR}
+
+	mockStorage.EXPECT().
+		GetOnDate(testifymock.Anything, internal.RUB, symbols, date).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.0103"), AsOfDate: &date},
+		}, nil).
+		Once()
+
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, date, internal.RUB, []internal.CurrencyCode{internal.EUR}).
+		Return(&internal.LatestRatesResponse{Date: date, Base: "RUB", Rates: map[string]string{"EUR": "0.0095"}}, nil).
+		Once()
+
+	mockWriter.EXPECT().
+		UpsertRatesMap(
+			testifymock.Anything,
+			internal.RUB,
+			date,
+			map[internal.CurrencyCode]decimal.Decimal{internal.EUR: decimal.RequireFromString("0.0095")},
+		).
+		Return(nil).
+		Once()
+
+	svc := internal.NewHistoricalRates(mockStorage, mockWriter, mockClient)
+	result, err := svc.Get(context.Background(), date, internal.RUB, symbols)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0.0103", result.Rates["USD"])
+	assert.Equal(t, "0.0095", result.Rates["EUR"])
+}
+
+func TestHistoricalRates_Get_RejectsAnotherDate(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+
+	// суббота: провайдер отвечает курсами пятницы
+	date := internal.Date{Time: time.Date(2024, 12, 28, 0, 0, 0, 0, time.UTC)}
+	friday := internal.Date{Time: time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC)}
+	symbols := []internal.CurrencyCode{internal.USD}
+
+	mockStorage.EXPECT().
+		GetOnDate(testifymock.Anything, internal.RUB, symbols, date).
+		Return(nil, nil).
+		Once()
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, date, internal.RUB, symbols).
+		Return(&internal.LatestRatesResponse{Date: friday, Base: "RUB", Rates: map[string]string{"USD": "0.0103"}}, nil).
+		Once()
+
+	svc := internal.NewHistoricalRates(mockStorage, mockWriter, mockClient)
+	_, err := svc.Get(context.Background(), date, internal.RUB, symbols)
+
+	require.ErrorIs(t, err, internal.ErrDateMismatch)
+	assert.Contains(t, err.Error(), "requested 2024-12-28, got 2024-12-27")
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateMatrix — курсы всех пар из Currencies: Rates[base][quote]. На диагонали 1.
+type RateMatrix struct {
+	Currencies []CurrencyCode                                    `json:"currencies"`
+	Rates      map[CurrencyCode]map[CurrencyCode]decimal.Decimal `json:"rates"`
+	Date       *Date                                             `json:"date,omitempty"` // самая старая дата среди ног
+	MixedDates bool                                              `json:"mixed_dates,omitempty"`
+}
+
+// GetMatrix считает курсы всех пар currencies по последним курсам. Все ноги берутся
+// из одного снимка хранилища, поэтому ячейки матрицы согласованы между собой.
+// Матрица всегда собирается через опорную валюту, CONVERSION_MODE на неё не влияет.
+func (s *RateConverter) GetMatrix(ctx context.Context, currencies []CurrencyCode) (RateMatrix, error) {
+	return s.matrix(ctx, currencies, func(ctx context.Context, quotes []CurrencyCode) ([]CurrencyLatestRate, error) {
+		if s.mixedDates == MixedDatesCommon {
+			return s.getLatestLegs(ctx, quotes)
+		}
+		// все quotes по base одним запросом — дешевле, чем перечислять их
+		rows, err := s.storage.GetLatest(ctx, s.pivot, nil)
+		if err != nil {
+			return nil, fmt.Errorf("get latest %s: %w", s.pivot, err)
+		}
+		return rows, nil
+	})
+}
+
+// GetMatrixOn — то же, что GetMatrix, но по курсам на дату date.
+func (s *RateConverter) GetMatrixOn(ctx context.Context, currencies []CurrencyCode, date Date) (RateMatrix, error) {
+	if date.IsZero() {
+		return RateMatrix{}, errors.New("date is empty")
+	}
+	return s.matrix(ctx, currencies, func(ctx context.Context, quotes []CurrencyCode) ([]CurrencyLatestRate, error) {
+		return s.getLegsOn(ctx, quotes, date)
+	})
+}
+
+func (s *RateConverter) matrix(ctx context.Context, currencies []CurrencyCode, load legsFunc) (RateMatrix, error) {
+	if len(currencies) < 2 {
+		return RateMatrix{}, errors.New("at least two currencies are required")
+	}
+
+	var quotes []CurrencyCode
+	seen := make(map[CurrencyCode]struct{}, len(currencies))
+	for _, c := range currencies {
+		if !c.IsSupported() {
+			return RateMatrix{}, errors.New("unsupported currency")
+		}
+		if _, dup := seen[c]; dup {
+			return RateMatrix{}, fmt.Errorf("duplicate currency %s", c)
+		}
+		seen[c] = struct{}{}
+		if c != s.pivot {
+			quotes = append(quotes, c)
+		}
+	}
+
+	var snapshot []CurrencyLatestRate
+	if len(quotes) > 0 {
+		var err error
+		snapshot, err = load(ctx, quotes)
+		if err != nil {
+			return RateMatrix{}, err
+		}
+	}
+	fromSnapshot := func(context.Context, []CurrencyCode) ([]CurrencyLatestRate, error) {
+		return snapshot, nil
+	}
+
+	out := RateMatrix{
+		Currencies: currencies,
+		Rates:      make(map[CurrencyCode]map[CurrencyCode]decimal.Decimal, len(currencies)),
+	}
+	var dates []*Date
+	for _, base := range currencies {
+		row := make(map[CurrencyCode]decimal.Decimal, len(currencies))
+		for _, quote := range currencies {
+			if quote == base {
+				row[quote] = decimal.NewFromInt(1)
+				continue
+			}
+			pr, err := s.pairRate(ctx, base, quote, fromSnapshot)
+			if err != nil {
+				return RateMatrix{}, fmt.Errorf("%s/%s: %w", base, quote, err)
+			}
+			row[quote] = pr.Rate
+			if pr.Date != nil {
+				dates = append(dates, pr.Date)
+			}
+			out.MixedDates = out.MixedDates || pr.MixedDates
+		}
+		out.Rates[base] = row
+	}
+	out.Date = oldestDate(dates)
+	return out, nil
+}
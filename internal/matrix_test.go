@@ -0,0 +1,51 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestRateConverter_GetMatrix_OneSnapshot(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	date := internal.Date{Time: time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.EXPECT().
+		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode(nil)).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &date},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &date},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.JPY, Rate: decimal.RequireFromString("1.5"), AsOfDate: &date},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	m, err := converter.GetMatrix(context.Background(), []internal.CurrencyCode{internal.USD, internal.EUR, internal.RUB})
+
+	require.NoError(t, err)
+	assert.Equal(t, date, *m.Date)
+	assert.False(t, m.MixedDates)
+	assert.Equal(t, "1", m.Rates[internal.USD][internal.USD].String())
+	assert.Equal(t, "0.9500", m.Rates[internal.USD][internal.EUR].StringFixed(4))
+	assert.Equal(t, "100.0000", m.Rates[internal.USD][internal.RUB].StringFixed(4))
+	assert.Equal(t, "0.0100", m.Rates[internal.RUB][internal.USD].StringFixed(4))
+	assert.Len(t, m.Rates[internal.EUR], 3)
+}
+
+func TestRateConverter_GetMatrix_Duplicate(t *testing.T) {
+	converter := internal.NewRateConverter(mock.NewMockStorage(t))
+
+	_, err := converter.GetMatrix(context.Background(), []internal.CurrencyCode{internal.USD, internal.USD})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate currency")
+}
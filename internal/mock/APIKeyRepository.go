@@ -4,6 +4,7 @@ package mock
 
 import (
 	context "context"
+	internal "service-currency/internal"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -21,24 +22,24 @@ func (_m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepository_Expecter {
 	return &MockAPIKeyRepository_Expecter{mock: &_m.Mock}
 }
 
-// GetStatusByHash provides a mock function with given fields: ctx, keyHash
-func (_m *MockAPIKeyRepository) GetStatusByHash(ctx context.Context, keyHash string) (bool, bool, error) {
+// GetByHash provides a mock function with given fields: ctx, keyHash
+func (_m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (internal.APIKey, bool, error) {
 	ret := _m.Called(ctx, keyHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetStatusByHash")
+		panic("no return value specified for GetByHash")
 	}
 
-	var r0 bool
+	var r0 internal.APIKey
 	var r1 bool
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (internal.APIKey, bool, error)); ok {
 		return rf(ctx, keyHash)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) internal.APIKey); ok {
 		r0 = rf(ctx, keyHash)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(internal.APIKey)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
@@ -56,31 +57,81 @@ func (_m *MockAPIKeyRepository) GetStatusByHash(ctx context.Context, keyHash str
 	return r0, r1, r2
 }
 
-// MockAPIKeyRepository_GetStatusByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStatusByHash'
-type MockAPIKeyRepository_GetStatusByHash_Call struct {
+// MockAPIKeyRepository_GetByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByHash'
+type MockAPIKeyRepository_GetByHash_Call struct {
 	*mock.Call
 }
 
-// GetStatusByHash is a helper method to define mock.On call
+// GetByHash is a helper method to define mock.On call
 //   - ctx context.Context
 //   - keyHash string
-func (_e *MockAPIKeyRepository_Expecter) GetStatusByHash(ctx interface{}, keyHash interface{}) *MockAPIKeyRepository_GetStatusByHash_Call {
-	return &MockAPIKeyRepository_GetStatusByHash_Call{Call: _e.mock.On("GetStatusByHash", ctx, keyHash)}
+func (_e *MockAPIKeyRepository_Expecter) GetByHash(ctx interface{}, keyHash interface{}) *MockAPIKeyRepository_GetByHash_Call {
+	return &MockAPIKeyRepository_GetByHash_Call{Call: _e.mock.On("GetByHash", ctx, keyHash)}
 }
 
-func (_c *MockAPIKeyRepository_GetStatusByHash_Call) Run(run func(ctx context.Context, keyHash string)) *MockAPIKeyRepository_GetStatusByHash_Call {
+func (_c *MockAPIKeyRepository_GetByHash_Call) Run(run func(ctx context.Context, keyHash string)) *MockAPIKeyRepository_GetByHash_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockAPIKeyRepository_GetStatusByHash_Call) Return(exists bool, isActive bool, err error) *MockAPIKeyRepository_GetStatusByHash_Call {
-	_c.Call.Return(exists, isActive, err)
+func (_c *MockAPIKeyRepository_GetByHash_Call) Return(key internal.APIKey, found bool, err error) *MockAPIKeyRepository_GetByHash_Call {
+	_c.Call.Return(key, found, err)
 	return _c
 }
 
-func (_c *MockAPIKeyRepository_GetStatusByHash_Call) RunAndReturn(run func(context.Context, string) (bool, bool, error)) *MockAPIKeyRepository_GetStatusByHash_Call {
+func (_c *MockAPIKeyRepository_GetByHash_Call) RunAndReturn(run func(context.Context, string) (internal.APIKey, bool, error)) *MockAPIKeyRepository_GetByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rehash provides a mock function with given fields: ctx, id, oldHash, newHash, version
+func (_m *MockAPIKeyRepository) Rehash(ctx context.Context, id int64, oldHash string, newHash string, version int) error {
+	ret := _m.Called(ctx, id, oldHash, newHash, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rehash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, int) error); ok {
+		r0 = rf(ctx, id, oldHash, newHash, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPIKeyRepository_Rehash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rehash'
+type MockAPIKeyRepository_Rehash_Call struct {
+	*mock.Call
+}
+
+// Rehash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - oldHash string
+//   - newHash string
+//   - version int
+func (_e *MockAPIKeyRepository_Expecter) Rehash(ctx interface{}, id interface{}, oldHash interface{}, newHash interface{}, version interface{}) *MockAPIKeyRepository_Rehash_Call {
+	return &MockAPIKeyRepository_Rehash_Call{Call: _e.mock.On("Rehash", ctx, id, oldHash, newHash, version)}
+}
+
+func (_c *MockAPIKeyRepository_Rehash_Call) Run(run func(ctx context.Context, id int64, oldHash string, newHash string, version int)) *MockAPIKeyRepository_Rehash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(string), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyRepository_Rehash_Call) Return(_a0 error) *MockAPIKeyRepository_Rehash_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPIKeyRepository_Rehash_Call) RunAndReturn(run func(context.Context, int64, string, string, int) error) *MockAPIKeyRepository_Rehash_Call {
 	_c.Call.Return(run)
 	return _c
 }
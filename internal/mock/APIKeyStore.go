@@ -0,0 +1,394 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+	internal "service-currency/internal"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyStore is an autogenerated mock type for the APIKeyStore type
+type MockAPIKeyStore struct {
+	mock.Mock
+}
+
+type MockAPIKeyStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAPIKeyStore) EXPECT() *MockAPIKeyStore_Expecter {
+	return &MockAPIKeyStore_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, keyHash, meta
+func (_m *MockAPIKeyStore) Create(ctx context.Context, keyHash string, meta internal.APIKey) (internal.APIKey, error) {
+	ret := _m.Called(ctx, keyHash, meta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 internal.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, internal.APIKey) (internal.APIKey, error)); ok {
+		return rf(ctx, keyHash, meta)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, internal.APIKey) internal.APIKey); ok {
+		r0 = rf(ctx, keyHash, meta)
+	} else {
+		r0 = ret.Get(0).(internal.APIKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, internal.APIKey) error); ok {
+		r1 = rf(ctx, keyHash, meta)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPIKeyStore_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockAPIKeyStore_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyHash string
+//   - meta internal.APIKey
+func (_e *MockAPIKeyStore_Expecter) Create(ctx interface{}, keyHash interface{}, meta interface{}) *MockAPIKeyStore_Create_Call {
+	return &MockAPIKeyStore_Create_Call{Call: _e.mock.On("Create", ctx, keyHash, meta)}
+}
+
+func (_c *MockAPIKeyStore_Create_Call) Run(run func(ctx context.Context, keyHash string, meta internal.APIKey)) *MockAPIKeyStore_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(internal.APIKey))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyStore_Create_Call) Return(_a0 internal.APIKey, _a1 error) *MockAPIKeyStore_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPIKeyStore_Create_Call) RunAndReturn(run func(context.Context, string, internal.APIKey) (internal.APIKey, error)) *MockAPIKeyStore_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockAPIKeyStore) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPIKeyStore_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockAPIKeyStore_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockAPIKeyStore_Expecter) Delete(ctx interface{}, id interface{}) *MockAPIKeyStore_Delete_Call {
+	return &MockAPIKeyStore_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockAPIKeyStore_Delete_Call) Run(run func(ctx context.Context, id int64)) *MockAPIKeyStore_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyStore_Delete_Call) Return(_a0 error) *MockAPIKeyStore_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPIKeyStore_Delete_Call) RunAndReturn(run func(context.Context, int64) error) *MockAPIKeyStore_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *MockAPIKeyStore) List(ctx context.Context) ([]internal.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []internal.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]internal.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []internal.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPIKeyStore_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockAPIKeyStore_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAPIKeyStore_Expecter) List(ctx interface{}) *MockAPIKeyStore_List_Call {
+	return &MockAPIKeyStore_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *MockAPIKeyStore_List_Call) Run(run func(ctx context.Context)) *MockAPIKeyStore_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyStore_List_Call) Return(_a0 []internal.APIKey, _a1 error) *MockAPIKeyStore_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPIKeyStore_List_Call) RunAndReturn(run func(context.Context) ([]internal.APIKey, error)) *MockAPIKeyStore_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function with given fields: ctx, id, reason
+func (_m *MockAPIKeyStore) Revoke(ctx context.Context, id int64, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPIKeyStore_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type MockAPIKeyStore_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - reason string
+func (_e *MockAPIKeyStore_Expecter) Revoke(ctx interface{}, id interface{}, reason interface{}) *MockAPIKeyStore_Revoke_Call {
+	return &MockAPIKeyStore_Revoke_Call{Call: _e.mock.On("Revoke", ctx, id, reason)}
+}
+
+func (_c *MockAPIKeyStore_Revoke_Call) Run(run func(ctx context.Context, id int64, reason string)) *MockAPIKeyStore_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyStore_Revoke_Call) Return(_a0 error) *MockAPIKeyStore_Revoke_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPIKeyStore_Revoke_Call) RunAndReturn(run func(context.Context, int64, string) error) *MockAPIKeyStore_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetActive provides a mock function with given fields: ctx, id, active
+func (_m *MockAPIKeyStore) SetActive(ctx context.Context, id int64, active bool) error {
+	ret := _m.Called(ctx, id, active)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetActive")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, id, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPIKeyStore_SetActive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetActive'
+type MockAPIKeyStore_SetActive_Call struct {
+	*mock.Call
+}
+
+// SetActive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - active bool
+func (_e *MockAPIKeyStore_Expecter) SetActive(ctx interface{}, id interface{}, active interface{}) *MockAPIKeyStore_SetActive_Call {
+	return &MockAPIKeyStore_SetActive_Call{Call: _e.mock.On("SetActive", ctx, id, active)}
+}
+
+func (_c *MockAPIKeyStore_SetActive_Call) Run(run func(ctx context.Context, id int64, active bool)) *MockAPIKeyStore_SetActive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyStore_SetActive_Call) Return(_a0 error) *MockAPIKeyStore_SetActive_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPIKeyStore_SetActive_Call) RunAndReturn(run func(context.Context, int64, bool) error) *MockAPIKeyStore_SetActive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPlan provides a mock function with given fields: ctx, id, plan, limits
+func (_m *MockAPIKeyStore) SetPlan(ctx context.Context, id int64, plan string, limits *internal.Limits) error {
+	ret := _m.Called(ctx, id, plan, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPlan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, *internal.Limits) error); ok {
+		r0 = rf(ctx, id, plan, limits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPIKeyStore_SetPlan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPlan'
+type MockAPIKeyStore_SetPlan_Call struct {
+	*mock.Call
+}
+
+// SetPlan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - plan string
+//   - limits *internal.Limits
+func (_e *MockAPIKeyStore_Expecter) SetPlan(ctx interface{}, id interface{}, plan interface{}, limits interface{}) *MockAPIKeyStore_SetPlan_Call {
+	return &MockAPIKeyStore_SetPlan_Call{Call: _e.mock.On("SetPlan", ctx, id, plan, limits)}
+}
+
+func (_c *MockAPIKeyStore_SetPlan_Call) Run(run func(ctx context.Context, id int64, plan string, limits *internal.Limits)) *MockAPIKeyStore_SetPlan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(*internal.Limits))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyStore_SetPlan_Call) Return(_a0 error) *MockAPIKeyStore_SetPlan_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPIKeyStore_SetPlan_Call) RunAndReturn(run func(context.Context, int64, string, *internal.Limits) error) *MockAPIKeyStore_SetPlan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateHash provides a mock function with given fields: ctx, id, keyHash, version
+func (_m *MockAPIKeyStore) UpdateHash(ctx context.Context, id int64, keyHash string, version int) error {
+	ret := _m.Called(ctx, id, keyHash, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int) error); ok {
+		r0 = rf(ctx, id, keyHash, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPIKeyStore_UpdateHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateHash'
+type MockAPIKeyStore_UpdateHash_Call struct {
+	*mock.Call
+}
+
+// UpdateHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - keyHash string
+//   - version int
+func (_e *MockAPIKeyStore_Expecter) UpdateHash(ctx interface{}, id interface{}, keyHash interface{}, version interface{}) *MockAPIKeyStore_UpdateHash_Call {
+	return &MockAPIKeyStore_UpdateHash_Call{Call: _e.mock.On("UpdateHash", ctx, id, keyHash, version)}
+}
+
+func (_c *MockAPIKeyStore_UpdateHash_Call) Run(run func(ctx context.Context, id int64, keyHash string, version int)) *MockAPIKeyStore_UpdateHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyStore_UpdateHash_Call) Return(_a0 error) *MockAPIKeyStore_UpdateHash_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPIKeyStore_UpdateHash_Call) RunAndReturn(run func(context.Context, int64, string, int) error) *MockAPIKeyStore_UpdateHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAPIKeyStore creates a new instance of MockAPIKeyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAPIKeyStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAPIKeyStore {
+	mock := &MockAPIKeyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
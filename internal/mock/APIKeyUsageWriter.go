@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockAPIKeyUsageWriter is an autogenerated mock type for the APIKeyUsageWriter type
+type MockAPIKeyUsageWriter struct {
+	mock.Mock
+}
+
+type MockAPIKeyUsageWriter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAPIKeyUsageWriter) EXPECT() *MockAPIKeyUsageWriter_Expecter {
+	return &MockAPIKeyUsageWriter_Expecter{mock: &_m.Mock}
+}
+
+// TouchLastUsed provides a mock function with given fields: ctx, lastUsed
+func (_m *MockAPIKeyUsageWriter) TouchLastUsed(ctx context.Context, lastUsed map[int64]time.Time) error {
+	ret := _m.Called(ctx, lastUsed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchLastUsed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[int64]time.Time) error); ok {
+		r0 = rf(ctx, lastUsed)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPIKeyUsageWriter_TouchLastUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchLastUsed'
+type MockAPIKeyUsageWriter_TouchLastUsed_Call struct {
+	*mock.Call
+}
+
+// TouchLastUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lastUsed map[int64]time.Time
+func (_e *MockAPIKeyUsageWriter_Expecter) TouchLastUsed(ctx interface{}, lastUsed interface{}) *MockAPIKeyUsageWriter_TouchLastUsed_Call {
+	return &MockAPIKeyUsageWriter_TouchLastUsed_Call{Call: _e.mock.On("TouchLastUsed", ctx, lastUsed)}
+}
+
+func (_c *MockAPIKeyUsageWriter_TouchLastUsed_Call) Run(run func(ctx context.Context, lastUsed map[int64]time.Time)) *MockAPIKeyUsageWriter_TouchLastUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(map[int64]time.Time))
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyUsageWriter_TouchLastUsed_Call) Return(_a0 error) *MockAPIKeyUsageWriter_TouchLastUsed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPIKeyUsageWriter_TouchLastUsed_Call) RunAndReturn(run func(context.Context, map[int64]time.Time) error) *MockAPIKeyUsageWriter_TouchLastUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAPIKeyUsageWriter creates a new instance of MockAPIKeyUsageWriter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAPIKeyUsageWriter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAPIKeyUsageWriter {
+	mock := &MockAPIKeyUsageWriter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,6 +4,7 @@ package mock
 
 import (
 	context "context"
+	internal "service-currency/internal"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -22,29 +23,29 @@ func (_m *MockAPIKeyValidator) EXPECT() *MockAPIKeyValidator_Expecter {
 }
 
 // Validate provides a mock function with given fields: ctx, rawKey
-func (_m *MockAPIKeyValidator) Validate(ctx context.Context, rawKey string) (bool, bool, error) {
+func (_m *MockAPIKeyValidator) Validate(ctx context.Context, rawKey string) (internal.Principal, internal.APIKeyStatus, error) {
 	ret := _m.Called(ctx, rawKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Validate")
 	}
 
-	var r0 bool
-	var r1 bool
+	var r0 internal.Principal
+	var r1 internal.APIKeyStatus
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (internal.Principal, internal.APIKeyStatus, error)); ok {
 		return rf(ctx, rawKey)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) internal.Principal); ok {
 		r0 = rf(ctx, rawKey)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(internal.Principal)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string) internal.APIKeyStatus); ok {
 		r1 = rf(ctx, rawKey)
 	} else {
-		r1 = ret.Get(1).(bool)
+		r1 = ret.Get(1).(internal.APIKeyStatus)
 	}
 
 	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
@@ -75,12 +76,12 @@ func (_c *MockAPIKeyValidator_Validate_Call) Run(run func(ctx context.Context, r
 	return _c
 }
 
-func (_c *MockAPIKeyValidator_Validate_Call) Return(exists bool, isActive bool, err error) *MockAPIKeyValidator_Validate_Call {
-	_c.Call.Return(exists, isActive, err)
+func (_c *MockAPIKeyValidator_Validate_Call) Return(principal internal.Principal, status internal.APIKeyStatus, err error) *MockAPIKeyValidator_Validate_Call {
+	_c.Call.Return(principal, status, err)
 	return _c
 }
 
-func (_c *MockAPIKeyValidator_Validate_Call) RunAndReturn(run func(context.Context, string) (bool, bool, error)) *MockAPIKeyValidator_Validate_Call {
+func (_c *MockAPIKeyValidator_Validate_Call) RunAndReturn(run func(context.Context, string) (internal.Principal, internal.APIKeyStatus, error)) *MockAPIKeyValidator_Validate_Call {
 	_c.Call.Return(run)
 	return _c
 }
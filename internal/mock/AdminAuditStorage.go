@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+	internal "service-currency/internal"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAdminAuditStorage is an autogenerated mock type for the AdminAuditStorage type
+type MockAdminAuditStorage struct {
+	mock.Mock
+}
+
+type MockAdminAuditStorage_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAdminAuditStorage) EXPECT() *MockAdminAuditStorage_Expecter {
+	return &MockAdminAuditStorage_Expecter{mock: &_m.Mock}
+}
+
+// InsertAdminAudit provides a mock function with given fields: ctx, entry
+func (_m *MockAdminAuditStorage) InsertAdminAudit(ctx context.Context, entry internal.AdminAuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertAdminAudit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.AdminAuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAdminAuditStorage_InsertAdminAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertAdminAudit'
+type MockAdminAuditStorage_InsertAdminAudit_Call struct {
+	*mock.Call
+}
+
+// InsertAdminAudit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry internal.AdminAuditEntry
+func (_e *MockAdminAuditStorage_Expecter) InsertAdminAudit(ctx interface{}, entry interface{}) *MockAdminAuditStorage_InsertAdminAudit_Call {
+	return &MockAdminAuditStorage_InsertAdminAudit_Call{Call: _e.mock.On("InsertAdminAudit", ctx, entry)}
+}
+
+func (_c *MockAdminAuditStorage_InsertAdminAudit_Call) Run(run func(ctx context.Context, entry internal.AdminAuditEntry)) *MockAdminAuditStorage_InsertAdminAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(internal.AdminAuditEntry))
+	})
+	return _c
+}
+
+func (_c *MockAdminAuditStorage_InsertAdminAudit_Call) Return(_a0 error) *MockAdminAuditStorage_InsertAdminAudit_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAdminAuditStorage_InsertAdminAudit_Call) RunAndReturn(run func(context.Context, internal.AdminAuditEntry) error) *MockAdminAuditStorage_InsertAdminAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAdminAuditStorage creates a new instance of MockAdminAuditStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAdminAuditStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAdminAuditStorage {
+	mock := &MockAdminAuditStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
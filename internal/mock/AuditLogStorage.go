@@ -22,17 +22,17 @@ func (_m *MockAuditLogStorage) EXPECT() *MockAuditLogStorage_Expecter {
 	return &MockAuditLogStorage_Expecter{mock: &_m.Mock}
 }
 
-// Insert provides a mock function with given fields: ctx, path, status, dateAsOf
-func (_m *MockAuditLogStorage) Insert(ctx context.Context, path string, status *int, dateAsOf *internal.Date) error {
-	ret := _m.Called(ctx, path, status, dateAsOf)
+// Insert provides a mock function with given fields: ctx, path, status, dateAsOf, keyID
+func (_m *MockAuditLogStorage) Insert(ctx context.Context, path string, status *int, dateAsOf *internal.Date, keyID *int64) error {
+	ret := _m.Called(ctx, path, status, dateAsOf, keyID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Insert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *int, *internal.Date) error); ok {
-		r0 = rf(ctx, path, status, dateAsOf)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *int, *internal.Date, *int64) error); ok {
+		r0 = rf(ctx, path, status, dateAsOf, keyID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -50,13 +50,14 @@ type MockAuditLogStorage_Insert_Call struct {
 //   - path string
 //   - status *int
 //   - dateAsOf *internal.Date
-func (_e *MockAuditLogStorage_Expecter) Insert(ctx interface{}, path interface{}, status interface{}, dateAsOf interface{}) *MockAuditLogStorage_Insert_Call {
-	return &MockAuditLogStorage_Insert_Call{Call: _e.mock.On("Insert", ctx, path, status, dateAsOf)}
+//   - keyID *int64
+func (_e *MockAuditLogStorage_Expecter) Insert(ctx interface{}, path interface{}, status interface{}, dateAsOf interface{}, keyID interface{}) *MockAuditLogStorage_Insert_Call {
+	return &MockAuditLogStorage_Insert_Call{Call: _e.mock.On("Insert", ctx, path, status, dateAsOf, keyID)}
 }
 
-func (_c *MockAuditLogStorage_Insert_Call) Run(run func(ctx context.Context, path string, status *int, dateAsOf *internal.Date)) *MockAuditLogStorage_Insert_Call {
+func (_c *MockAuditLogStorage_Insert_Call) Run(run func(ctx context.Context, path string, status *int, dateAsOf *internal.Date, keyID *int64)) *MockAuditLogStorage_Insert_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(*int), args[3].(*internal.Date))
+		run(args[0].(context.Context), args[1].(string), args[2].(*int), args[3].(*internal.Date), args[4].(*int64))
 	})
 	return _c
 }
@@ -66,7 +67,7 @@ func (_c *MockAuditLogStorage_Insert_Call) Return(_a0 error) *MockAuditLogStorag
 	return _c
 }
 
-func (_c *MockAuditLogStorage_Insert_Call) RunAndReturn(run func(context.Context, string, *int, *internal.Date) error) *MockAuditLogStorage_Insert_Call {
+func (_c *MockAuditLogStorage_Insert_Call) RunAndReturn(run func(context.Context, string, *int, *internal.Date, *int64) error) *MockAuditLogStorage_Insert_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,98 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+	internal "service-currency/internal"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockRateLimiter is an autogenerated mock type for the RateLimiter type
+type MockRateLimiter struct {
+	mock.Mock
+}
+
+type MockRateLimiter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRateLimiter) EXPECT() *MockRateLimiter_Expecter {
+	return &MockRateLimiter_Expecter{mock: &_m.Mock}
+}
+
+// Allow provides a mock function with given fields: ctx, keyID, limits, now
+func (_m *MockRateLimiter) Allow(ctx context.Context, keyID int64, limits internal.Limits, now time.Time) (internal.RateDecision, error) {
+	ret := _m.Called(ctx, keyID, limits, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allow")
+	}
+
+	var r0 internal.RateDecision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, internal.Limits, time.Time) (internal.RateDecision, error)); ok {
+		return rf(ctx, keyID, limits, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, internal.Limits, time.Time) internal.RateDecision); ok {
+		r0 = rf(ctx, keyID, limits, now)
+	} else {
+		r0 = ret.Get(0).(internal.RateDecision)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, internal.Limits, time.Time) error); ok {
+		r1 = rf(ctx, keyID, limits, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRateLimiter_Allow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Allow'
+type MockRateLimiter_Allow_Call struct {
+	*mock.Call
+}
+
+// Allow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyID int64
+//   - limits internal.Limits
+//   - now time.Time
+func (_e *MockRateLimiter_Expecter) Allow(ctx interface{}, keyID interface{}, limits interface{}, now interface{}) *MockRateLimiter_Allow_Call {
+	return &MockRateLimiter_Allow_Call{Call: _e.mock.On("Allow", ctx, keyID, limits, now)}
+}
+
+func (_c *MockRateLimiter_Allow_Call) Run(run func(ctx context.Context, keyID int64, limits internal.Limits, now time.Time)) *MockRateLimiter_Allow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(internal.Limits), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockRateLimiter_Allow_Call) Return(_a0 internal.RateDecision, _a1 error) *MockRateLimiter_Allow_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRateLimiter_Allow_Call) RunAndReturn(run func(context.Context, int64, internal.Limits, time.Time) (internal.RateDecision, error)) *MockRateLimiter_Allow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRateLimiter creates a new instance of MockRateLimiter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRateLimiter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRateLimiter {
+	mock := &MockRateLimiter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+	internal "service-currency/internal"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRepairLogStorage is an autogenerated mock type for the RepairLogStorage type
+type MockRepairLogStorage struct {
+	mock.Mock
+}
+
+type MockRepairLogStorage_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRepairLogStorage) EXPECT() *MockRepairLogStorage_Expecter {
+	return &MockRepairLogStorage_Expecter{mock: &_m.Mock}
+}
+
+// InsertRepair provides a mock function with given fields: ctx, repair
+func (_m *MockRepairLogStorage) InsertRepair(ctx context.Context, repair internal.RateRepair) error {
+	ret := _m.Called(ctx, repair)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRepair")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.RateRepair) error); ok {
+		r0 = rf(ctx, repair)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRepairLogStorage_InsertRepair_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertRepair'
+type MockRepairLogStorage_InsertRepair_Call struct {
+	*mock.Call
+}
+
+// InsertRepair is a helper method to define mock.On call
+//   - ctx context.Context
+//   - repair internal.RateRepair
+func (_e *MockRepairLogStorage_Expecter) InsertRepair(ctx interface{}, repair interface{}) *MockRepairLogStorage_InsertRepair_Call {
+	return &MockRepairLogStorage_InsertRepair_Call{Call: _e.mock.On("InsertRepair", ctx, repair)}
+}
+
+func (_c *MockRepairLogStorage_InsertRepair_Call) Run(run func(ctx context.Context, repair internal.RateRepair)) *MockRepairLogStorage_InsertRepair_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(internal.RateRepair))
+	})
+	return _c
+}
+
+func (_c *MockRepairLogStorage_InsertRepair_Call) Return(_a0 error) *MockRepairLogStorage_InsertRepair_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRepairLogStorage_InsertRepair_Call) RunAndReturn(run func(context.Context, internal.RateRepair) error) *MockRepairLogStorage_InsertRepair_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRepairLogStorage creates a new instance of MockRepairLogStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRepairLogStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRepairLogStorage {
+	mock := &MockRepairLogStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,6 +22,123 @@ func (_m *MockStorage) EXPECT() *MockStorage_Expecter {
 	return &MockStorage_Expecter{mock: &_m.Mock}
 }
 
+// GetAllLatest provides a mock function with given fields: ctx
+func (_m *MockStorage) GetAllLatest(ctx context.Context) ([]internal.CurrencyLatestRate, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllLatest")
+	}
+
+	var r0 []internal.CurrencyLatestRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]internal.CurrencyLatestRate, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []internal.CurrencyLatestRate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.CurrencyLatestRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetAllLatest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllLatest'
+type MockStorage_GetAllLatest_Call struct {
+	*mock.Call
+}
+
+// GetAllLatest is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockStorage_Expecter) GetAllLatest(ctx interface{}) *MockStorage_GetAllLatest_Call {
+	return &MockStorage_GetAllLatest_Call{Call: _e.mock.On("GetAllLatest", ctx)}
+}
+
+func (_c *MockStorage_GetAllLatest_Call) Run(run func(ctx context.Context)) *MockStorage_GetAllLatest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetAllLatest_Call) Return(_a0 []internal.CurrencyLatestRate, _a1 error) *MockStorage_GetAllLatest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetAllLatest_Call) RunAndReturn(run func(context.Context) ([]internal.CurrencyLatestRate, error)) *MockStorage_GetAllLatest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllOnDate provides a mock function with given fields: ctx, date
+func (_m *MockStorage) GetAllOnDate(ctx context.Context, date internal.Date) ([]internal.CurrencyLatestRate, error) {
+	ret := _m.Called(ctx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllOnDate")
+	}
+
+	var r0 []internal.CurrencyLatestRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Date) ([]internal.CurrencyLatestRate, error)); ok {
+		return rf(ctx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Date) []internal.CurrencyLatestRate); ok {
+		r0 = rf(ctx, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.CurrencyLatestRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Date) error); ok {
+		r1 = rf(ctx, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetAllOnDate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllOnDate'
+type MockStorage_GetAllOnDate_Call struct {
+	*mock.Call
+}
+
+// GetAllOnDate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - date internal.Date
+func (_e *MockStorage_Expecter) GetAllOnDate(ctx interface{}, date interface{}) *MockStorage_GetAllOnDate_Call {
+	return &MockStorage_GetAllOnDate_Call{Call: _e.mock.On("GetAllOnDate", ctx, date)}
+}
+
+func (_c *MockStorage_GetAllOnDate_Call) Run(run func(ctx context.Context, date internal.Date)) *MockStorage_GetAllOnDate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(internal.Date))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetAllOnDate_Call) Return(_a0 []internal.CurrencyLatestRate, _a1 error) *MockStorage_GetAllOnDate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetAllOnDate_Call) RunAndReturn(run func(context.Context, internal.Date) ([]internal.CurrencyLatestRate, error)) *MockStorage_GetAllOnDate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetLatest provides a mock function with given fields: ctx, base, quotes
 func (_m *MockStorage) GetLatest(ctx context.Context, base internal.CurrencyCode, quotes []internal.CurrencyCode) ([]internal.CurrencyLatestRate, error) {
 	ret := _m.Called(ctx, base, quotes)
@@ -82,6 +199,249 @@ func (_c *MockStorage_GetLatest_Call) RunAndReturn(run func(context.Context, int
 	return _c
 }
 
+// GetLatestCommon provides a mock function with given fields: ctx, base, quotes
+func (_m *MockStorage) GetLatestCommon(ctx context.Context, base internal.CurrencyCode, quotes []internal.CurrencyCode) ([]internal.CurrencyLatestRate, error) {
+	ret := _m.Called(ctx, base, quotes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestCommon")
+	}
+
+	var r0 []internal.CurrencyLatestRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode) ([]internal.CurrencyLatestRate, error)); ok {
+		return rf(ctx, base, quotes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode) []internal.CurrencyLatestRate); ok {
+		r0 = rf(ctx, base, quotes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.CurrencyLatestRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode) error); ok {
+		r1 = rf(ctx, base, quotes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetLatestCommon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestCommon'
+type MockStorage_GetLatestCommon_Call struct {
+	*mock.Call
+}
+
+// GetLatestCommon is a helper method to define mock.On call
+//   - ctx context.Context
+//   - base internal.CurrencyCode
+//   - quotes []internal.CurrencyCode
+func (_e *MockStorage_Expecter) GetLatestCommon(ctx interface{}, base interface{}, quotes interface{}) *MockStorage_GetLatestCommon_Call {
+	return &MockStorage_GetLatestCommon_Call{Call: _e.mock.On("GetLatestCommon", ctx, base, quotes)}
+}
+
+func (_c *MockStorage_GetLatestCommon_Call) Run(run func(ctx context.Context, base internal.CurrencyCode, quotes []internal.CurrencyCode)) *MockStorage_GetLatestCommon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(internal.CurrencyCode), args[2].([]internal.CurrencyCode))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetLatestCommon_Call) Return(_a0 []internal.CurrencyLatestRate, _a1 error) *MockStorage_GetLatestCommon_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetLatestCommon_Call) RunAndReturn(run func(context.Context, internal.CurrencyCode, []internal.CurrencyCode) ([]internal.CurrencyLatestRate, error)) *MockStorage_GetLatestCommon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLegs provides a mock function with given fields: ctx, base, legs
+func (_m *MockStorage) GetLegs(ctx context.Context, base internal.CurrencyCode, legs []internal.RateLeg) ([]internal.CurrencyLatestRate, error) {
+	ret := _m.Called(ctx, base, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLegs")
+	}
+
+	var r0 []internal.CurrencyLatestRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.RateLeg) ([]internal.CurrencyLatestRate, error)); ok {
+		return rf(ctx, base, legs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.RateLeg) []internal.CurrencyLatestRate); ok {
+		r0 = rf(ctx, base, legs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.CurrencyLatestRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.CurrencyCode, []internal.RateLeg) error); ok {
+		r1 = rf(ctx, base, legs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetLegs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLegs'
+type MockStorage_GetLegs_Call struct {
+	*mock.Call
+}
+
+// GetLegs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - base internal.CurrencyCode
+//   - legs []internal.RateLeg
+func (_e *MockStorage_Expecter) GetLegs(ctx interface{}, base interface{}, legs interface{}) *MockStorage_GetLegs_Call {
+	return &MockStorage_GetLegs_Call{Call: _e.mock.On("GetLegs", ctx, base, legs)}
+}
+
+func (_c *MockStorage_GetLegs_Call) Run(run func(ctx context.Context, base internal.CurrencyCode, legs []internal.RateLeg)) *MockStorage_GetLegs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(internal.CurrencyCode), args[2].([]internal.RateLeg))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetLegs_Call) Return(_a0 []internal.CurrencyLatestRate, _a1 error) *MockStorage_GetLegs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetLegs_Call) RunAndReturn(run func(context.Context, internal.CurrencyCode, []internal.RateLeg) ([]internal.CurrencyLatestRate, error)) *MockStorage_GetLegs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOnDate provides a mock function with given fields: ctx, base, quotes, date
+func (_m *MockStorage) GetOnDate(ctx context.Context, base internal.CurrencyCode, quotes []internal.CurrencyCode, date internal.Date) ([]internal.CurrencyLatestRate, error) {
+	ret := _m.Called(ctx, base, quotes, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOnDate")
+	}
+
+	var r0 []internal.CurrencyLatestRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date) ([]internal.CurrencyLatestRate, error)); ok {
+		return rf(ctx, base, quotes, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date) []internal.CurrencyLatestRate); ok {
+		r0 = rf(ctx, base, quotes, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.CurrencyLatestRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date) error); ok {
+		r1 = rf(ctx, base, quotes, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetOnDate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOnDate'
+type MockStorage_GetOnDate_Call struct {
+	*mock.Call
+}
+
+// GetOnDate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - base internal.CurrencyCode
+//   - quotes []internal.CurrencyCode
+//   - date internal.Date
+func (_e *MockStorage_Expecter) GetOnDate(ctx interface{}, base interface{}, quotes interface{}, date interface{}) *MockStorage_GetOnDate_Call {
+	return &MockStorage_GetOnDate_Call{Call: _e.mock.On("GetOnDate", ctx, base, quotes, date)}
+}
+
+func (_c *MockStorage_GetOnDate_Call) Run(run func(ctx context.Context, base internal.CurrencyCode, quotes []internal.CurrencyCode, date internal.Date)) *MockStorage_GetOnDate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(internal.CurrencyCode), args[2].([]internal.CurrencyCode), args[3].(internal.Date))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetOnDate_Call) Return(_a0 []internal.CurrencyLatestRate, _a1 error) *MockStorage_GetOnDate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetOnDate_Call) RunAndReturn(run func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date) ([]internal.CurrencyLatestRate, error)) *MockStorage_GetOnDate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRange provides a mock function with given fields: ctx, base, quotes, from, to
+func (_m *MockStorage) GetRange(ctx context.Context, base internal.CurrencyCode, quotes []internal.CurrencyCode, from internal.Date, to internal.Date) ([]internal.CurrencyLatestRate, error) {
+	ret := _m.Called(ctx, base, quotes, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRange")
+	}
+
+	var r0 []internal.CurrencyLatestRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date, internal.Date) ([]internal.CurrencyLatestRate, error)); ok {
+		return rf(ctx, base, quotes, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date, internal.Date) []internal.CurrencyLatestRate); ok {
+		r0 = rf(ctx, base, quotes, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.CurrencyLatestRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date, internal.Date) error); ok {
+		r1 = rf(ctx, base, quotes, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStorage_GetRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRange'
+type MockStorage_GetRange_Call struct {
+	*mock.Call
+}
+
+// GetRange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - base internal.CurrencyCode
+//   - quotes []internal.CurrencyCode
+//   - from internal.Date
+//   - to internal.Date
+func (_e *MockStorage_Expecter) GetRange(ctx interface{}, base interface{}, quotes interface{}, from interface{}, to interface{}) *MockStorage_GetRange_Call {
+	return &MockStorage_GetRange_Call{Call: _e.mock.On("GetRange", ctx, base, quotes, from, to)}
+}
+
+func (_c *MockStorage_GetRange_Call) Run(run func(ctx context.Context, base internal.CurrencyCode, quotes []internal.CurrencyCode, from internal.Date, to internal.Date)) *MockStorage_GetRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(internal.CurrencyCode), args[2].([]internal.CurrencyCode), args[3].(internal.Date), args[4].(internal.Date))
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetRange_Call) Return(_a0 []internal.CurrencyLatestRate, _a1 error) *MockStorage_GetRange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStorage_GetRange_Call) RunAndReturn(run func(context.Context, internal.CurrencyCode, []internal.CurrencyCode, internal.Date, internal.Date) ([]internal.CurrencyLatestRate, error)) *MockStorage_GetRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockStorage creates a new instance of MockStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockStorage(t interface {
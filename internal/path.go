@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxPathHops ограничивает длину цепочки: каждый шаг добавляет погрешность и старит курс.
+const maxPathHops = 4
+
+type rateEdge struct {
+	to   CurrencyCode
+	rate decimal.Decimal
+	date Date
+}
+
+// rateGraph — сохранённые курсы как граф: ребро есть в обе стороны,
+// обратное ребро несёт 1/rate. Из параллельных рёбер остаётся самое свежее.
+type rateGraph map[CurrencyCode]map[CurrencyCode]rateEdge
+
+func newRateGraph(rows []CurrencyLatestRate) rateGraph {
+	g := make(rateGraph)
+	for _, r := range rows {
+		if r.AsOfDate == nil || r.Rate.Sign() <= 0 || r.BaseCCY == r.QuoteCCY {
+			continue
+		}
+		g.add(r.BaseCCY, r.QuoteCCY, r.Rate, *r.AsOfDate)
+		g.add(r.QuoteCCY, r.BaseCCY, decimal.NewFromInt(1).Div(r.Rate), *r.AsOfDate)
+	}
+	return g
+}
+
+func (g rateGraph) add(from, to CurrencyCode, rate decimal.Decimal, date Date) {
+	edges, ok := g[from]
+	if !ok {
+		edges = make(map[CurrencyCode]rateEdge)
+		g[from] = edges
+	}
+	if cur, ok := edges[to]; ok && !date.After(cur.date.Time) {
+		return
+	}
+	edges[to] = rateEdge{to: to, rate: rate, date: date}
+}
+
+type pathStep struct {
+	prev   CurrencyCode
+	edge   rateEdge
+	oldest Date // самая старая дата среди рёбер пути до этой вершины
+}
+
+// shortest ищет путь from->to с наименьшим числом шагов, а среди таких —
+// с самой свежей самой старой ногой. Обход идёт слоями: префикс кратчайшего пути
+// сам кратчайший, поэтому на каждом слое достаточно лучшего значения для вершины.
+func (g rateGraph) shortest(from, to CurrencyCode) ([]rateEdge, []CurrencyCode, bool) {
+	best := map[CurrencyCode]pathStep{from: {}}
+	layer := []CurrencyCode{from}
+
+	for hop := 0; hop < maxPathHops && len(layer) > 0; hop++ {
+		next := make(map[CurrencyCode]pathStep)
+		for _, u := range layer {
+			for v, e := range g[u] {
+				if _, seen := best[v]; seen {
+					continue
+				}
+				oldest := e.date
+				if u != from && best[u].oldest.Before(oldest.Time) {
+					oldest = best[u].oldest
+				}
+				// при равной свежести берём меньший код, чтобы ответ не зависел от обхода map
+				if cur, ok := next[v]; ok && (oldest.Before(cur.oldest.Time) || oldest.Equal(cur.oldest.Time) && u >= cur.prev) {
+					continue
+				}
+				next[v] = pathStep{prev: u, edge: e, oldest: oldest}
+			}
+		}
+
+		layer = layer[:0]
+		for v, st := range next {
+			best[v] = st
+			layer = append(layer, v)
+		}
+
+		if _, ok := next[to]; ok {
+			break
+		}
+	}
+
+	if _, ok := best[to]; !ok || from == to {
+		return nil, nil, false
+	}
+
+	var edges []rateEdge
+	path := []CurrencyCode{to}
+	for v := to; v != from; v = best[v].prev {
+		edges = append([]rateEdge{best[v].edge}, edges...)
+		path = append([]CurrencyCode{best[v].prev}, path...)
+	}
+	return edges, path, true
+}
+
+// findPath собирает курс base->quote цепочкой по курсам из load.
+// found=false — цепочки нет, вызывающий может попробовать другой способ.
+func (s *RateConverter) findPath(
+	ctx context.Context,
+	base, quote CurrencyCode,
+	load func(ctx context.Context) ([]CurrencyLatestRate, error),
+) (pr PairRate, found bool, err error) {
+	if base == quote {
+		return PairRate{}, false, nil
+	}
+
+	rows, err := load(ctx)
+	if err != nil {
+		return PairRate{}, false, fmt.Errorf("load rates graph: %w", err)
+	}
+
+	edges, path, ok := newRateGraph(rows).shortest(base, quote)
+	if !ok {
+		return PairRate{}, false, nil
+	}
+
+	rate := decimal.NewFromInt(1)
+	legs := make([]PairLeg, len(edges))
+	dates := make([]*Date, len(edges))
+	for i, e := range edges {
+		rate = rate.Mul(e.rate)
+		d := e.date
+		dates[i] = &d
+		legs[i] = PairLeg{Base: path[i], Quote: e.to, Rate: e.rate, Date: &d}
+	}
+
+	out := PairRate{Base: base, Quote: quote, Rate: rate, Date: oldestDate(dates), Path: path}
+	if !sameDates(dates) {
+		switch s.mixedDates {
+		case MixedDatesReject:
+			return PairRate{}, false, fmt.Errorf("%w: path %s", ErrMixedDates, joinCodes(path))
+		case MixedDatesCommon:
+			// общую дату для цепочки не ищем — пусть соберёт pivot
+			return PairRate{}, false, nil
+		}
+		out.MixedDates = true
+		out.Legs = legs
+	}
+	return out, true, nil
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pepper — версия ENCODING_KEY, секрета HMAC, которым хешируются API-ключи.
+type Pepper struct {
+	Version int
+	Key     string
+}
+
+// Peppers — версии секрета от текущей к старым. Новые ключи хешируются текущей версией;
+// ключ со старой версией проверяется ею и при первом успешном использовании перехешируется
+// текущей. Когда в api_keys не останется старой версии, её можно убрать из конфига.
+type Peppers []Pepper
+
+// NewPeppers проверяет версии и упорядочивает их от новой к старой.
+func NewPeppers(list ...Pepper) (Peppers, error) {
+	if len(list) == 0 {
+		return nil, errors.New("no encoding keys")
+	}
+
+	out := make(Peppers, 0, len(list))
+	seen := make(map[int]struct{}, len(list))
+	for _, p := range list {
+		p.Key = strings.TrimSpace(p.Key)
+		if p.Version < 1 {
+			return nil, fmt.Errorf("encoding key version must be positive, got %d", p.Version)
+		}
+		if p.Key == "" {
+			return nil, fmt.Errorf("encoding key version %d is empty", p.Version)
+		}
+		if _, dup := seen[p.Version]; dup {
+			return nil, fmt.Errorf("duplicate encoding key version %d", p.Version)
+		}
+		seen[p.Version] = struct{}{}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version > out[j].Version })
+	return out, nil
+}
+
+// Current — версия, которой хешируются новые и перехешируются старые ключи.
+func (p Peppers) Current() Pepper {
+	return p[0]
+}
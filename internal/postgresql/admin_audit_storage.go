@@ -0,0 +1,28 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"service-currency/internal"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AdminAuditStorage struct {
+	pgpool *pgxpool.Pool
+}
+
+func NewAdminAuditStorage(pgpool *pgxpool.Pool) *AdminAuditStorage {
+	return &AdminAuditStorage{pgpool: pgpool}
+}
+
+func (s *AdminAuditStorage) InsertAdminAudit(ctx context.Context, entry internal.AdminAuditEntry) error {
+	_, err := s.pgpool.Exec(ctx, `
+insert into admin_audit_log (actor, action, key_id, status)
+values ($1, $2, $3, $4);
+`, entry.Actor, entry.Action, entry.KeyID, entry.Status)
+	if err != nil {
+		return fmt.Errorf("insert admin_audit_log: %w", err)
+	}
+	return nil
+}
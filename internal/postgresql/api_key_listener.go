@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// apiKeysChannel — канал уведомлений триггера api_keys_changed, payload — id ключа.
+const apiKeysChannel = "api_keys_changed"
+
+// APIKeyListener слушает изменения api_keys через LISTEN/NOTIFY.
+type APIKeyListener struct {
+	pool  *pgxpool.Pool
+	retry time.Duration
+}
+
+func NewAPIKeyListener(pool *pgxpool.Pool) *APIKeyListener {
+	return &APIKeyListener{pool: pool, retry: 5 * time.Second}
+}
+
+// Run вызывает changed(id) на каждое изменение ключа, пока не отменён ctx.
+// После (пере)подключения вызывает reset: уведомления за время разрыва потеряны.
+func (l *APIKeyListener) Run(ctx context.Context, changed func(id int64), reset func()) error {
+	for {
+		err := l.listen(ctx, changed, reset)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Printf("api key listener: %v, reconnecting in %s", err, l.retry)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(l.retry):
+		}
+	}
+}
+
+func (l *APIKeyListener) listen(ctx context.Context, changed func(id int64), reset func()) error {
+	pooled, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// соединение с LISTEN не возвращаем в пул
+	conn := pooled.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	_, err = conn.Exec(ctx, "listen "+apiKeysChannel)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", apiKeysChannel, err)
+	}
+	reset()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		id, err := strconv.ParseInt(n.Payload, 10, 64)
+		if err != nil {
+			log.Printf("api key listener: unexpected payload %q", n.Payload)
+			reset()
+			continue
+		}
+		changed(id)
+	}
+}
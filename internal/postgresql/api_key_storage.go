@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"service-currency/internal"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -18,23 +20,219 @@ func NewAPIKeyStorage(pool *pgxpool.Pool) *APIKeyStorage {
 	return &APIKeyStorage{pool: pool}
 }
 
-func (s *APIKeyStorage) GetStatusByHash(ctx context.Context, keyHash string) (exists bool, isActive bool, err error) {
+func (s *APIKeyStorage) GetByHash(ctx context.Context, keyHash string) (internal.APIKey, bool, error) {
 	keyHash = strings.TrimSpace(keyHash)
 	if keyHash == "" {
-		return false, false, nil
+		return internal.APIKey{}, false, nil
 	}
 
-	err = s.pool.QueryRow(ctx, `
-select is_active
+	key, err := scanAPIKey(s.pool.QueryRow(ctx, `
+select `+apiKeyColumns+`
 from api_keys
 where key_hash = $1;
-`, keyHash).Scan(&isActive)
+`, keyHash))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return false, false, nil
+			return internal.APIKey{}, false, nil
 		}
-		return false, false, fmt.Errorf("select api_keys: %w", err)
+		return internal.APIKey{}, false, fmt.Errorf("select api_keys: %w", err)
 	}
 
-	return true, isActive, nil
+	return key, true, nil
+}
+
+const apiKeyColumns = `id, is_active, owner, description, created_by, created_at, expires_at,
+  revoked_at, revocation_reason, last_used_at, scopes, plan, rate_per_second, burst, monthly_quota, key_hash_version`
+
+func scanAPIKey(row pgx.Row) (internal.APIKey, error) {
+	var key internal.APIKey
+	var rate *float64
+	var burst *int
+	var quota *int64
+	err := row.Scan(&key.ID, &key.IsActive, &key.Owner, &key.Description, &key.CreatedBy, &key.CreatedAt, &key.ExpiresAt,
+		&key.RevokedAt, &key.RevocationReason, &key.LastUsedAt, &key.Scopes, &key.Plan, &rate, &burst, &quota, &key.HashVersion)
+	if rate != nil || burst != nil || quota != nil {
+		key.Limits = &internal.Limits{}
+		if rate != nil {
+			key.Limits.RatePerSecond = *rate
+		}
+		if burst != nil {
+			key.Limits.Burst = *burst
+		}
+		if quota != nil {
+			key.Limits.MonthlyQuota = *quota
+		}
+	}
+	return key, err
+}
+
+// limitColumns раскладывает свои ограничения ключа по колонкам; nil — все null.
+func limitColumns(l *internal.Limits) (rate *float64, burst *int, quota *int64) {
+	if l == nil {
+		return nil, nil, nil
+	}
+	return &l.RatePerSecond, &l.Burst, &l.MonthlyQuota
+}
+
+func (s *APIKeyStorage) Create(ctx context.Context, keyHash string, meta internal.APIKey) (internal.APIKey, error) {
+	rate, burst, quota := limitColumns(meta.Limits)
+	key, err := scanAPIKey(s.pool.QueryRow(ctx, `
+insert into api_keys (key_hash, owner, description, created_by, expires_at, scopes,
+  plan, rate_per_second, burst, monthly_quota, key_hash_version)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+returning `+apiKeyColumns+`;
+`, keyHash, meta.Owner, meta.Description, meta.CreatedBy, meta.ExpiresAt, meta.Scopes,
+		meta.Plan, rate, burst, quota, meta.HashVersion))
+	if err != nil {
+		return internal.APIKey{}, fmt.Errorf("insert api_keys: %w", err)
+	}
+	return key, nil
+}
+
+func (s *APIKeyStorage) List(ctx context.Context) ([]internal.APIKey, error) {
+	rows, err := s.pool.Query(ctx, `
+select `+apiKeyColumns+`
+from api_keys
+order by id;
+`)
+	if err != nil {
+		return nil, fmt.Errorf("select api_keys: %w", err)
+	}
+	defer rows.Close()
+
+	var out []internal.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api_keys: %w", err)
+		}
+		out = append(out, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows api_keys: %w", err)
+	}
+	return out, nil
+}
+
+func (s *APIKeyStorage) SetActive(ctx context.Context, id int64, active bool) error {
+	var revoked bool
+	err := s.pool.QueryRow(ctx, `
+with target as (
+  select id, revoked_at is not null as revoked
+  from api_keys
+  where id = $1
+),
+upd as (
+  update api_keys k
+  set is_active = $2
+  from target t
+  where k.id = t.id and not (t.revoked and $2)
+)
+select revoked from target;
+`, id, active).Scan(&revoked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return internal.ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("update api_keys: %w", err)
+	}
+	if revoked && active {
+		return internal.ErrAPIKeyRevoked
+	}
+	return nil
+}
+
+func (s *APIKeyStorage) Revoke(ctx context.Context, id int64, reason string) error {
+	tag, err := s.pool.Exec(ctx, `
+update api_keys
+set is_active = false,
+    revoked_at = coalesce(revoked_at, now()),
+    revocation_reason = $2
+where id = $1;
+`, id, reason)
+	if err != nil {
+		return fmt.Errorf("update api_keys: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return internal.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed обновляет last_used_at пачкой; время только сдвигается вперёд.
+func (s *APIKeyStorage) TouchLastUsed(ctx context.Context, lastUsed map[int64]time.Time) error {
+	ids := make([]int64, 0, len(lastUsed))
+	times := make([]time.Time, 0, len(lastUsed))
+	for id, at := range lastUsed {
+		ids = append(ids, id)
+		times = append(times, at)
+	}
+
+	_, err := s.pool.Exec(ctx, `
+update api_keys k
+set last_used_at = u.at
+from unnest($1::bigint[], $2::timestamptz[]) as u(id, at)
+where k.id = u.id and (k.last_used_at is null or k.last_used_at < u.at);
+`, ids, times)
+	if err != nil {
+		return fmt.Errorf("update api_keys last_used_at: %w", err)
+	}
+	return nil
+}
+
+func (s *APIKeyStorage) UpdateHash(ctx context.Context, id int64, keyHash string, version int) error {
+	tag, err := s.pool.Exec(ctx, `
+update api_keys
+set key_hash = $2, key_hash_version = $3
+where id = $1;
+`, id, keyHash, version)
+	if err != nil {
+		return fmt.Errorf("update api_keys: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return internal.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *APIKeyStorage) Rehash(ctx context.Context, id int64, oldHash, newHash string, version int) error {
+	_, err := s.pool.Exec(ctx, `
+update api_keys
+set key_hash = $3, key_hash_version = $4
+where id = $1 and key_hash = $2;
+`, id, oldHash, newHash, version)
+	if err != nil {
+		return fmt.Errorf("update api_keys: %w", err)
+	}
+	return nil
+}
+
+func (s *APIKeyStorage) SetPlan(ctx context.Context, id int64, plan string, limits *internal.Limits) error {
+	rate, burst, quota := limitColumns(limits)
+	tag, err := s.pool.Exec(ctx, `
+update api_keys
+set plan = $2, rate_per_second = $3, burst = $4, monthly_quota = $5
+where id = $1;
+`, id, plan, rate, burst, quota)
+	if err != nil {
+		return fmt.Errorf("update api_keys: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return internal.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *APIKeyStorage) Delete(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, `
+delete from api_keys
+where id = $1;
+`, id)
+	if err != nil {
+		return fmt.Errorf("delete api_keys: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return internal.ErrAPIKeyNotFound
+	}
+	return nil
 }
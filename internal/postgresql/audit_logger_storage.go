@@ -18,7 +18,7 @@ func NewRequestLogStorage(pgpool *pgxpool.Pool) *RequestLogStorage {
 	return &RequestLogStorage{pgpool: pgpool}
 }
 
-func (s *RequestLogStorage) Insert(ctx context.Context, path string, status *int, dateAsOf *internal.Date) error {
+func (s *RequestLogStorage) Insert(ctx context.Context, path string, status *int, dateAsOf *internal.Date, keyID *int64) error {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		path = "unknown"
@@ -31,9 +31,9 @@ func (s *RequestLogStorage) Insert(ctx context.Context, path string, status *int
 	}
 
 	_, err := s.pgpool.Exec(ctx, `
-insert into request_log (path, status, date_as_of)
-values ($1, $2, $3::date);
-`, path, status, asOf)
+insert into request_log (path, status, date_as_of, api_key_id)
+values ($1, $2, $3::date, $4);
+`, path, status, asOf, keyID)
 	if err != nil {
 		return fmt.Errorf("insert request_log: %w", err)
 	}
@@ -9,6 +9,7 @@ import (
 
 	"service-currency/internal"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
 )
@@ -21,6 +22,8 @@ func NewCurrencyStorage(pgpool *pgxpool.Pool) *CurrencyStorage {
 	return &CurrencyStorage{pgpool: pgpool}
 }
 
+// UpsertRatesMap сохраняет курсы на дату asOfDate. История по другим датам не трогается,
+// повторная запись на ту же дату обновляет курс.
 func (c *CurrencyStorage) UpsertRatesMap(
 	ctx context.Context,
 	base internal.CurrencyCode,
@@ -35,7 +38,7 @@ func (c *CurrencyStorage) UpsertRatesMap(
 		return fmt.Errorf("as_of_date is empty")
 	}
 
-	asOf := time.Date(asOfDate.Year(), asOfDate.Month(), asOfDate.Day(), 0, 0, 0, 0, time.UTC)
+	asOf := toDBDate(asOfDate)
 
 	tx, err := c.pgpool.Begin(ctx)
 	if err != nil {
@@ -53,9 +56,8 @@ func (c *CurrencyStorage) UpsertRatesMap(
 		_, err := tx.Exec(ctx, `
 insert into currency_rate (base_ccy, quote_ccy, as_of_date, rate, fetched_at)
 values ($1, $2, $3::date, $4::numeric, now())
-on conflict (base_ccy, quote_ccy)
+on conflict (base_ccy, quote_ccy, as_of_date)
 do update set
-  as_of_date = excluded.as_of_date,
   rate = excluded.rate,
   fetched_at = now();
 `, baseStr, quoteStr, asOf, rate.String())
@@ -77,13 +79,15 @@ func (c *CurrencyStorage) GetLatest(
 	base internal.CurrencyCode,
 	quotes []internal.CurrencyCode,
 ) ([]internal.CurrencyLatestRate, error) {
-	baseStr := strings.ToUpper(strings.TrimSpace(base.String()))
-	if baseStr == "" {
-		return nil, errors.New("base currency is empty")
+	baseStr, norm, ok, err := normalizePair(base, quotes)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []internal.CurrencyLatestRate{}, nil
 	}
 
-	if len(quotes) == 0 {
-		rows, err := c.pgpool.Query(ctx, `
+	rows, err := c.pgpool.Query(ctx, `
 select distinct on (quote_ccy)
   base_ccy,
   quote_ccy,
@@ -91,52 +95,244 @@ select distinct on (quote_ccy)
   as_of_date,
   fetched_at
 from currency_rate
-where base_ccy = $1
+where base_ccy = $1 and (cardinality($2::text[]) = 0 or quote_ccy = any($2))
 order by quote_ccy, as_of_date desc, fetched_at desc;
-`, baseStr)
-		if err != nil {
-			return nil, fmt.Errorf("query latest rates: %w", err)
+`, baseStr, norm)
+	if err != nil {
+		return nil, fmt.Errorf("query latest rates: %w", err)
+	}
+	return scanRates(rows)
+}
+
+// GetLatestCommon возвращает курсы всех quotes на последнюю дату, на которую сохранены все они.
+// Одним запросом, так что все ноги берутся из одного снимка данных.
+func (c *CurrencyStorage) GetLatestCommon(
+	ctx context.Context,
+	base internal.CurrencyCode,
+	quotes []internal.CurrencyCode,
+) ([]internal.CurrencyLatestRate, error) {
+	baseStr, norm, ok, err := normalizePair(base, quotes)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || len(norm) == 0 {
+		return []internal.CurrencyLatestRate{}, nil
+	}
+
+	rows, err := c.pgpool.Query(ctx, `
+with common as (
+  select as_of_date
+  from currency_rate
+  where base_ccy = $1 and quote_ccy = any($2::text[])
+  group by as_of_date
+  having count(distinct quote_ccy) = cardinality($2::text[])
+  order by as_of_date desc
+  limit 1
+)
+select r.base_ccy, r.quote_ccy, r.rate::text, r.as_of_date, r.fetched_at
+from currency_rate r
+join common c on c.as_of_date = r.as_of_date
+where r.base_ccy = $1 and r.quote_ccy = any($2::text[])
+order by r.quote_ccy;
+`, baseStr, uniqueStrings(norm))
+	if err != nil {
+		return nil, fmt.Errorf("query latest common rates: %w", err)
+	}
+	return scanRates(rows)
+}
+
+// GetOnDate возвращает курсы ровно на дату date. Если quotes пустой — все quotes для base.
+func (c *CurrencyStorage) GetOnDate(
+	ctx context.Context,
+	base internal.CurrencyCode,
+	quotes []internal.CurrencyCode,
+	date internal.Date,
+) ([]internal.CurrencyLatestRate, error) {
+	if date.IsZero() {
+		return nil, errors.New("date is empty")
+	}
+
+	baseStr, norm, ok, err := normalizePair(base, quotes)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []internal.CurrencyLatestRate{}, nil
+	}
+
+	rows, err := c.pgpool.Query(ctx, `
+select base_ccy, quote_ccy, rate::text, as_of_date, fetched_at
+from currency_rate
+where base_ccy = $1
+  and (cardinality($2::text[]) = 0 or quote_ccy = any($2))
+  and as_of_date = $3::date
+order by quote_ccy;
+`, baseStr, norm, toDBDate(date))
+	if err != nil {
+		return nil, fmt.Errorf("query rates on %s: %w", date.Format("2006-01-02"), err)
+	}
+	return scanRates(rows)
+}
+
+// GetRange возвращает курсы за период [from, to] включительно, по quote и дате по возрастанию.
+// Если quotes пустой — все quotes для base.
+func (c *CurrencyStorage) GetRange(
+	ctx context.Context,
+	base internal.CurrencyCode,
+	quotes []internal.CurrencyCode,
+	from, to internal.Date,
+) ([]internal.CurrencyLatestRate, error) {
+	if from.IsZero() || to.IsZero() {
+		return nil, errors.New("date range is empty")
+	}
+	if to.Before(from.Time) {
+		return nil, fmt.Errorf("invalid range: %s is after %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	baseStr, norm, ok, err := normalizePair(base, quotes)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []internal.CurrencyLatestRate{}, nil
+	}
+
+	rows, err := c.pgpool.Query(ctx, `
+select base_ccy, quote_ccy, rate::text, as_of_date, fetched_at
+from currency_rate
+where base_ccy = $1
+  and (cardinality($2::text[]) = 0 or quote_ccy = any($2))
+  and as_of_date between $3::date and $4::date
+order by quote_ccy, as_of_date;
+`, baseStr, norm, toDBDate(from), toDBDate(to))
+	if err != nil {
+		return nil, fmt.Errorf("query rates range: %w", err)
+	}
+	return scanRates(rows)
+}
+
+// GetLegs читает одним запросом последние курсы для legs без даты и курсы на даты для остальных.
+// Для quote, запрошенной и без даты, и с датой, строки могут совпасть — union их схлопывает.
+func (c *CurrencyStorage) GetLegs(
+	ctx context.Context,
+	base internal.CurrencyCode,
+	legs []internal.RateLeg,
+) ([]internal.CurrencyLatestRate, error) {
+	baseStr := strings.ToUpper(strings.TrimSpace(base.String()))
+	if baseStr == "" {
+		return nil, errors.New("base currency is empty")
+	}
+
+	latest := make([]string, 0, len(legs))
+	datedQuotes := make([]string, 0, len(legs))
+	datedDates := make([]time.Time, 0, len(legs))
+	for _, l := range legs {
+		qs := strings.ToUpper(strings.TrimSpace(l.Quote.String()))
+		if qs == "" || qs == baseStr {
+			continue
 		}
-		defer rows.Close()
-
-		var out []internal.CurrencyLatestRate
-		for rows.Next() {
-			var r internal.CurrencyLatestRate
-			var bRaw, qRaw, rateText string
-			var asOf time.Time
-
-			if err := rows.Scan(&bRaw, &qRaw, &rateText, &asOf, &r.FetchedAt); err != nil {
-				return nil, fmt.Errorf("scan: %w", err)
-			}
-
-			b, err := internal.NewCurrencyCode(strings.TrimSpace(bRaw))
-			if err != nil {
-				return nil, fmt.Errorf("bad base_ccy from db %q: %w", bRaw, err)
-			}
-			q, err := internal.NewCurrencyCode(strings.TrimSpace(qRaw))
-			if err != nil {
-				return nil, fmt.Errorf("bad quote_ccy from db %q: %w", qRaw, err)
-			}
-
-			r.BaseCCY = b
-			r.QuoteCCY = q
-
-			rateText = strings.TrimSpace(rateText)
-			if rateText == "" {
-				return nil, fmt.Errorf("empty rate for %s/%s", r.BaseCCY, r.QuoteCCY)
-			}
-			rate, err := decimal.NewFromString(rateText)
-			if err != nil {
-				return nil, fmt.Errorf("parse rate %s/%s=%q: %w", r.BaseCCY, r.QuoteCCY, rateText, err)
-			}
-			r.Rate = rate
-
-			d := internal.Date{Time: time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)}
-			r.AsOfDate = &d
-
-			out = append(out, r)
+		if l.Date == nil {
+			latest = append(latest, qs)
+			continue
 		}
-		return out, rows.Err()
+		datedQuotes = append(datedQuotes, qs)
+		datedDates = append(datedDates, toDBDate(*l.Date))
+	}
+	if len(latest) == 0 && len(datedQuotes) == 0 {
+		return []internal.CurrencyLatestRate{}, nil
+	}
+
+	rows, err := c.pgpool.Query(ctx, `
+(
+  select distinct on (quote_ccy)
+    base_ccy,
+    quote_ccy,
+    rate::text,
+    as_of_date,
+    fetched_at
+  from currency_rate
+  where base_ccy = $1 and quote_ccy = any($2::text[])
+  order by quote_ccy, as_of_date desc, fetched_at desc
+)
+union
+(
+  select r.base_ccy, r.quote_ccy, r.rate::text, r.as_of_date, r.fetched_at
+  from currency_rate r
+  join unnest($3::text[], $4::date[]) as l(quote_ccy, as_of_date)
+    on r.quote_ccy = l.quote_ccy and r.as_of_date = l.as_of_date
+  where r.base_ccy = $1
+);
+`, baseStr, uniqueStrings(latest), datedQuotes, datedDates)
+	if err != nil {
+		return nil, fmt.Errorf("query rate legs: %w", err)
+	}
+	return scanRates(rows)
+}
+
+// GetAllLatest возвращает последний курс для каждой пары (base_ccy, quote_ccy) в БД.
+// Запрос вызывается на каждый /api/v1/rate в режиме path, поэтому не сортирует всю историю:
+// пары перебираются прыжками по первичному ключу, для каждой берётся одна последняя строка.
+func (c *CurrencyStorage) GetAllLatest(ctx context.Context) ([]internal.CurrencyLatestRate, error) {
+	rows, err := c.pgpool.Query(ctx, `
+with recursive pairs as (
+  (
+    select base_ccy, quote_ccy
+    from currency_rate
+    order by base_ccy, quote_ccy
+    limit 1
+  )
+  union all
+  select n.base_ccy, n.quote_ccy
+  from pairs p
+  cross join lateral (
+    select c.base_ccy, c.quote_ccy
+    from currency_rate c
+    where (c.base_ccy, c.quote_ccy) > (p.base_ccy, p.quote_ccy)
+    order by c.base_ccy, c.quote_ccy
+    limit 1
+  ) n
+)
+select r.base_ccy, r.quote_ccy, r.rate::text, r.as_of_date, r.fetched_at
+from pairs p
+cross join lateral (
+  select c.base_ccy, c.quote_ccy, c.rate, c.as_of_date, c.fetched_at
+  from currency_rate c
+  where c.base_ccy = p.base_ccy and c.quote_ccy = p.quote_ccy
+  order by c.as_of_date desc
+  limit 1
+) r
+order by r.base_ccy, r.quote_ccy;
+`)
+	if err != nil {
+		return nil, fmt.Errorf("query latest rates: %w", err)
+	}
+	return scanRates(rows)
+}
+
+// GetAllOnDate возвращает курсы всех пар ровно на дату date.
+func (c *CurrencyStorage) GetAllOnDate(ctx context.Context, date internal.Date) ([]internal.CurrencyLatestRate, error) {
+	if date.IsZero() {
+		return nil, errors.New("date is empty")
+	}
+
+	rows, err := c.pgpool.Query(ctx, `
+select base_ccy, quote_ccy, rate::text, as_of_date, fetched_at
+from currency_rate
+where as_of_date = $1::date
+order by base_ccy, quote_ccy;
+`, toDBDate(date))
+	if err != nil {
+		return nil, fmt.Errorf("query rates on %s: %w", date.Format("2006-01-02"), err)
+	}
+	return scanRates(rows)
+}
+
+// normalizePair приводит base и quotes к виду, в котором они лежат в БД.
+// ok=false — quotes были заданы, но после нормализации не осталось ни одной (запрашивать нечего).
+func normalizePair(base internal.CurrencyCode, quotes []internal.CurrencyCode) (string, []string, bool, error) {
+	baseStr := strings.ToUpper(strings.TrimSpace(base.String()))
+	if baseStr == "" {
+		return "", nil, false, errors.New("base currency is empty")
 	}
 
 	norm := make([]string, 0, len(quotes))
@@ -146,24 +342,25 @@ order by quote_ccy, as_of_date desc, fetched_at desc;
 			norm = append(norm, qs)
 		}
 	}
-	if len(norm) == 0 {
-		return []internal.CurrencyLatestRate{}, nil
+	if len(quotes) > 0 && len(norm) == 0 {
+		return baseStr, nil, false, nil
 	}
+	return baseStr, norm, true, nil
+}
 
-	rows, err := c.pgpool.Query(ctx, `
-select distinct on (quote_ccy)
-  base_ccy,
-  quote_ccy,
-  rate::text,
-  as_of_date,
-  fetched_at
-from currency_rate
-where base_ccy = $1 and quote_ccy = any($2)
-order by quote_ccy, as_of_date desc, fetched_at desc;
-`, baseStr, norm)
-	if err != nil {
-		return nil, fmt.Errorf("query latest rates: %w", err)
+func uniqueStrings(in []string) []string {
+	seen := make(map[string]struct{}, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if _, ok := seen[s]; !ok {
+			seen[s] = struct{}{}
+			out = append(out, s)
+		}
 	}
+	return out
+}
+
+func scanRates(rows pgx.Rows) ([]internal.CurrencyLatestRate, error) {
 	defer rows.Close()
 
 	var out []internal.CurrencyLatestRate
@@ -205,3 +402,7 @@ order by quote_ccy, as_of_date desc, fetched_at desc;
 	}
 	return out, rows.Err()
 }
+
+func toDBDate(d internal.Date) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+}
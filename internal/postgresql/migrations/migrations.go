@@ -2,109 +2,300 @@ package migrations
 
 import (
 	"context"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockID — ключ advisory lock: два процесса не накатывают миграции одновременно.
+const lockID int64 = 0x63757272656e6379 // "currency"
+
+// Migration — пара файлов sql/NNNN_name.up.sql и sql/NNNN_name.down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time // nil — ещё не применена
+	// Missing — версия записана в schema_migrations, но её нет в бинарнике.
+	Missing bool
+}
+
 type Migrations struct {
-	pool *pgxpool.Pool
+	pool       *pgxpool.Pool
+	migrations []Migration
 }
 
 func New(pool *pgxpool.Pool) *Migrations {
 	return &Migrations{pool: pool}
 }
 
-func (m *Migrations) Setup(ctx context.Context) error {
-	if err := m.setupLatestTable(ctx); err != nil {
-		return fmt.Errorf("setup currency_rate: %w", err)
-	}
-	if err := m.setupRequestLogTable(ctx); err != nil {
-		return fmt.Errorf("setup request_log: %w", err)
+// Up применяет все ещё не применённые миграции по возрастанию версии, каждую в своей транзакции.
+func (m *Migrations) Up(ctx context.Context) ([]Migration, error) {
+	all, err := m.load()
+	if err != nil {
+		return nil, err
 	}
 
-	if err := m.createAPIKeysTable(ctx); err != nil {
-		return fmt.Errorf("create api_keys: %w", err)
+	var done []Migration
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range all {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			err = apply(ctx, conn, mig.Up, `insert into schema_migrations (version, name) values ($1, $2);`, mig.Version, mig.Name)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down откатывает steps последних применённых миграций.
+func (m *Migrations) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, errors.New("steps must be positive")
 	}
-	if err := m.fillAPIKeys(ctx); err != nil {
-		return fmt.Errorf("seed api_keys: %w", err)
+	all, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]Migration, len(all))
+	for _, mig := range all {
+		byVersion[mig.Version] = mig
 	}
 
-	return nil
+	var done []Migration
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, v := range versions[:min(steps, len(versions))] {
+			mig, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("migration %04d is applied but not known to this binary", v)
+			}
+			err = apply(ctx, conn, mig.Down, `delete from schema_migrations where version = $1;`, mig.Version)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
 }
 
-func (m *Migrations) setupLatestTable(ctx context.Context) error {
-	_, err := m.pool.Exec(ctx, `
-create table if not exists currency_rate (
-  base_ccy   char(3) not null,
-  quote_ccy  char(3) not null,
-  as_of_date date not null,
-  rate       numeric(20, 10) not null,
-  fetched_at timestamptz not null default now(),
-  primary key (base_ccy, quote_ccy)
-);
+// Status — все известные и записанные в БД версии по возрастанию.
+func (m *Migrations) Status(ctx context.Context) ([]Status, error) {
+	all, err := m.load()
+	if err != nil {
+		return nil, err
+	}
 
-create index if not exists idx_currency_rate_lookup
-  on currency_rate (base_ccy, quote_ccy, as_of_date desc);
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
 
-create index if not exists idx_currency_rate_fetched_at
-  on currency_rate (fetched_at desc);
-`)
+	err = ensureTable(ctx, conn)
 	if err != nil {
-		return fmt.Errorf("ensure table currency_rate: %w", err)
+		return nil, err
 	}
-	return nil
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Status, 0, len(all))
+	for _, mig := range all {
+		st := Status{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			st.AppliedAt = &a.at
+			delete(applied, mig.Version)
+		}
+		out = append(out, st)
+	}
+	for v, a := range applied {
+		at := a.at
+		out = append(out, Status{Version: v, Name: a.name, AppliedAt: &at, Missing: true})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
 }
 
-func (m *Migrations) setupRequestLogTable(ctx context.Context) error {
-	_, err := m.pool.Exec(ctx, `
-create table if not exists request_log (
-  id          bigserial primary key,
-  path        text not null,
-  status      integer,
-  date_as_of  date,
-  created_at  timestamptz not null default now()
-);
+// locked выполняет fn на одном соединении под advisory lock.
+func (m *Migrations) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
 
-create index if not exists idx_request_log_created_at
-  on request_log (created_at desc);
+	_, err = conn.Exec(ctx, `select pg_advisory_lock($1);`, lockID)
+	if err != nil {
+		return fmt.Errorf("advisory lock: %w", err)
+	}
+	defer func() {
+		// контекст мог быть отменён — снимаем блокировку независимо от него
+		_, _ = conn.Exec(context.Background(), `select pg_advisory_unlock($1);`, lockID)
+	}()
 
-create index if not exists idx_request_log_path_created_at
-  on request_log (path, created_at desc);
-`)
+	err = ensureTable(ctx, conn)
 	if err != nil {
-		return fmt.Errorf("ensure table request_log: %w", err)
+		return err
 	}
-	return nil
+	return fn(conn)
 }
 
-func (m *Migrations) createAPIKeysTable(ctx context.Context) error {
-	_, err := m.pool.Exec(ctx, `
-create table if not exists api_keys (
-  id         bigserial primary key,
-  key_hash   char(64) not null unique,
-  is_active  boolean not null default true,
-  created_at timestamptz not null default now(),
-  constraint api_keys_key_hash_hex_len_chk check (length(key_hash) = 64)
+func ensureTable(ctx context.Context, conn *pgxpool.Conn) error {
+	_, err := conn.Exec(ctx, `
+create table if not exists schema_migrations (
+  version    integer primary key,
+  name       text not null,
+  applied_at timestamptz not null default now()
 );
 `)
 	if err != nil {
-		return fmt.Errorf("ensure table api_keys: %w", err)
+		return fmt.Errorf("ensure table schema_migrations: %w", err)
 	}
 	return nil
 }
 
-// тестовый метод проверить работу пары ключей
-func (m *Migrations) fillAPIKeys(ctx context.Context) error {
-	_, err := m.pool.Exec(ctx, `
-insert into api_keys (key_hash, is_active)
-values
-  ('304b4b2fc46274d0706fee081c40a26b8ff37ae67f0f3fd1c2d037311ea30b2d', true),
-  ('a09324edba7323a60743768654cab2b9f50759a465612461ed4aed473752a05e', false)
-on conflict (key_hash) do nothing;
-`)
+type appliedMigration struct {
+	name string
+	at   time.Time
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.Query(ctx, `select version, name, applied_at from schema_migrations;`)
 	if err != nil {
-		return fmt.Errorf("seed api_keys: %w", err)
+		return nil, fmt.Errorf("select schema_migrations: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	out := make(map[int]appliedMigration)
+	for rows.Next() {
+		var v int
+		var a appliedMigration
+		if err := rows.Scan(&v, &a.name, &a.at); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		out[v] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows schema_migrations: %w", err)
+	}
+	return out, nil
+}
+
+// apply выполняет sql миграции и запись в schema_migrations в одной транзакции.
+func apply(ctx context.Context, conn *pgxpool.Conn, sql, record string, args ...any) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, record, args...)
+		return err
+	})
+}
+
+func (m *Migrations) load() ([]Migration, error) {
+	if m.migrations == nil {
+		all, err := Load(files)
+		if err != nil {
+			return nil, err
+		}
+		m.migrations = all
+	}
+	return m.migrations, nil
+}
+
+var fileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load читает миграции из sql/ в fsys. У каждой версии должны быть оба файла,
+// версии идут подряд с 1.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "sql/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, name := range names {
+		parts := fileRe.FindStringSubmatch(path.Base(name))
+		if parts == nil {
+			return nil, fmt.Errorf("migration %s: expected NNNN_name.up.sql or NNNN_name.down.sql", name)
+		}
+		version, _ := strconv.Atoi(parts[1])
+
+		body, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != parts[2] {
+			return nil, fmt.Errorf("migration %04d: names differ: %s and %s", version, mig.Name, parts[2])
+		}
+		if parts[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		out = append(out, *mig)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+
+	for i, mig := range out {
+		if mig.Version != i+1 {
+			return nil, fmt.Errorf("migration %04d: expected version %04d", mig.Version, i+1)
+		}
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: both up and down files are required", mig.Version, mig.Name)
+		}
+	}
+	return out, nil
 }
@@ -0,0 +1,47 @@
+package migrations_test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/postgresql/migrations"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	all, err := migrations.Load(os.DirFS("."))
+
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	assert.Equal(t, "create_currency_rate", all[0].Name)
+}
+
+func TestLoad_MissingDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0001_init.up.sql":   {Data: []byte("create table a ();")},
+		"sql/0001_init.down.sql": {Data: []byte("drop table a;")},
+		"sql/0002_next.up.sql":   {Data: []byte("create table b ();")},
+	}
+
+	_, err := migrations.Load(fsys)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "both up and down")
+}
+
+func TestLoad_Gap(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0001_init.up.sql":   {Data: []byte("create table a ();")},
+		"sql/0001_init.down.sql": {Data: []byte("drop table a;")},
+		"sql/0003_next.up.sql":   {Data: []byte("create table b ();")},
+		"sql/0003_next.down.sql": {Data: []byte("drop table b;")},
+	}
+
+	_, err := migrations.Load(fsys)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected version 0002")
+}
@@ -0,0 +1,32 @@
+create table if not exists currency_rate (
+  base_ccy   char(3) not null,
+  quote_ccy  char(3) not null,
+  as_of_date date not null,
+  rate       numeric(20, 10) not null,
+  fetched_at timestamptz not null default now(),
+  primary key (base_ccy, quote_ccy, as_of_date)
+);
+
+create index if not exists idx_currency_rate_fetched_at
+  on currency_rate (fetched_at desc);
+
+-- базы до истории курсов: ключ (base_ccy, quote_ccy), курс перезаписывался.
+-- Строки сохраняются: в старой схеме пара была уникальна.
+do $$
+begin
+  if exists (
+    select 1
+    from pg_constraint
+    where conrelid = 'currency_rate'::regclass
+      and contype = 'p'
+      and cardinality(conkey) = 2
+  ) then
+    alter table currency_rate drop constraint currency_rate_pkey;
+    alter table currency_rate add constraint currency_rate_pkey
+      primary key (base_ccy, quote_ccy, as_of_date);
+  end if;
+end
+$$;
+
+-- покрывается первичным ключом
+drop index if exists idx_currency_rate_lookup;
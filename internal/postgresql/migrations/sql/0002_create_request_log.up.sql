@@ -0,0 +1,13 @@
+create table if not exists request_log (
+  id          bigserial primary key,
+  path        text not null,
+  status      integer,
+  date_as_of  date,
+  created_at  timestamptz not null default now()
+);
+
+create index if not exists idx_request_log_created_at
+  on request_log (created_at desc);
+
+create index if not exists idx_request_log_path_created_at
+  on request_log (path, created_at desc);
@@ -0,0 +1,7 @@
+create table if not exists api_keys (
+  id         bigserial primary key,
+  key_hash   char(64) not null unique,
+  is_active  boolean not null default true,
+  created_at timestamptz not null default now(),
+  constraint api_keys_key_hash_hex_len_chk check (length(key_hash) = 64)
+);
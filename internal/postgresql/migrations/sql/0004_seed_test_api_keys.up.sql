@@ -0,0 +1,6 @@
+-- тестовая пара ключей: активный и выключенный
+insert into api_keys (key_hash, is_active)
+values
+  ('304b4b2fc46274d0706fee081c40a26b8ff37ae67f0f3fd1c2d037311ea30b2d', true),
+  ('a09324edba7323a60743768654cab2b9f50759a465612461ed4aed473752a05e', false)
+on conflict (key_hash) do nothing;
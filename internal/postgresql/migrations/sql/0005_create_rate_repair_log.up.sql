@@ -0,0 +1,12 @@
+-- журнал Reconciler: какие дни и валюты догружались и чем закончилось
+create table if not exists rate_repair_log (
+  id          bigserial primary key,
+  base_ccy    char(3) not null,
+  as_of_date  date not null,
+  quotes      text[] not null,
+  error       text,
+  created_at  timestamptz not null default now()
+);
+
+create index if not exists idx_rate_repair_log_created_at
+  on rate_repair_log (created_at desc);
@@ -0,0 +1,2 @@
+-- публичные тестовые ключи обратно не возвращаем: откат не должен открывать доступ
+select 1;
@@ -0,0 +1,6 @@
+-- ключи из 0004 известны всем, кто видел репозиторий; рабочие ключи выпускает `apikey create`
+delete from api_keys
+where key_hash in (
+  '304b4b2fc46274d0706fee081c40a26b8ff37ae67f0f3fd1c2d037311ea30b2d',
+  'a09324edba7323a60743768654cab2b9f50759a465612461ed4aed473752a05e'
+);
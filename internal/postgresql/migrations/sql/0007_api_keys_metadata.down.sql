@@ -0,0 +1,7 @@
+drop table if exists admin_audit_log;
+
+alter table api_keys
+  drop column if exists owner,
+  drop column if exists description,
+  drop column if exists created_by,
+  drop column if exists expires_at;
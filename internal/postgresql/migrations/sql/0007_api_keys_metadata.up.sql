@@ -0,0 +1,18 @@
+alter table api_keys
+  add column if not exists owner       text not null default '',
+  add column if not exists description text not null default '',
+  add column if not exists created_by  text not null default '',
+  add column if not exists expires_at  timestamptz;
+
+-- действия администраторов над ключами; request_log — только для запросов к API курсов
+create table if not exists admin_audit_log (
+  id          bigserial primary key,
+  actor       text not null,
+  action      text not null,
+  key_id      bigint,
+  status      integer not null,
+  created_at  timestamptz not null default now()
+);
+
+create index if not exists idx_admin_audit_log_created_at
+  on admin_audit_log (created_at desc);
@@ -0,0 +1,4 @@
+alter table api_keys
+  drop column if exists revoked_at,
+  drop column if exists revocation_reason,
+  drop column if exists last_used_at;
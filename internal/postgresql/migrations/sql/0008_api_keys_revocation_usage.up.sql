@@ -0,0 +1,4 @@
+alter table api_keys
+  add column if not exists revoked_at        timestamptz,
+  add column if not exists revocation_reason text not null default '',
+  add column if not exists last_used_at      timestamptz;
@@ -0,0 +1,7 @@
+drop index if exists idx_request_log_api_key_created_at;
+
+alter table request_log
+  drop column if exists api_key_id;
+
+alter table api_keys
+  drop column if exists plan;
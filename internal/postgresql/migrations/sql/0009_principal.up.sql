@@ -0,0 +1,9 @@
+alter table api_keys
+  add column if not exists plan text not null default 'default';
+
+-- чей это был запрос; ключ могут удалить, а журнал остаётся
+alter table request_log
+  add column if not exists api_key_id bigint;
+
+create index if not exists idx_request_log_api_key_created_at
+  on request_log (api_key_id, created_at desc);
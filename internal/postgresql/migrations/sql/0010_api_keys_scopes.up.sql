@@ -0,0 +1,7 @@
+alter table api_keys
+  add column if not exists scopes text[] not null default '{}';
+
+-- до появления прав ключи открывали всё — сохраняем им этот доступ
+update api_keys
+set scopes = array['rates:latest', 'rates:historical', 'convert']
+where scopes = '{}';
@@ -0,0 +1,7 @@
+drop table if exists api_key_monthly_usage;
+drop table if exists api_key_rate_buckets;
+
+alter table api_keys
+  drop column if exists monthly_quota,
+  drop column if exists burst,
+  drop column if exists rate_per_second;
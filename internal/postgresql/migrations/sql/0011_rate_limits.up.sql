@@ -0,0 +1,20 @@
+-- свои ограничения ключа; null — по тарифу plan
+alter table api_keys
+  add column if not exists rate_per_second double precision,
+  add column if not exists burst           integer,
+  add column if not exists monthly_quota   bigint;
+
+-- состояние токен-бакетов для postgresql.RateLimiter
+create table if not exists api_key_rate_buckets (
+  key_id     bigint primary key,
+  tokens     double precision not null,
+  updated_at timestamptz not null
+);
+
+-- запросы ключа за месяц (UTC), месяц — его первое число
+create table if not exists api_key_monthly_usage (
+  key_id   bigint not null,
+  month    date not null,
+  requests bigint not null default 0,
+  primary key (key_id, month)
+);
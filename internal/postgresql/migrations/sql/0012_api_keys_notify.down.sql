@@ -0,0 +1,3 @@
+drop trigger if exists api_keys_changed_delete on api_keys;
+drop trigger if exists api_keys_changed_update on api_keys;
+drop function if exists notify_api_keys_changed();
@@ -0,0 +1,27 @@
+-- кеш ключей в репликах сбрасывает ключ по уведомлению, см. postgresql.APIKeyListener;
+-- last_used_at меняется постоянно и на проверку ключа не влияет — его не сообщаем
+create or replace function notify_api_keys_changed() returns trigger as $$
+begin
+  if tg_op = 'DELETE' then
+    perform pg_notify('api_keys_changed', old.id::text);
+    return old;
+  end if;
+  perform pg_notify('api_keys_changed', new.id::text);
+  return new;
+end;
+$$ language plpgsql;
+
+create trigger api_keys_changed_update
+  after update on api_keys
+  for each row
+  when ((old.key_hash, old.owner, old.is_active, old.expires_at, old.revoked_at, old.scopes, old.plan,
+         old.rate_per_second, old.burst, old.monthly_quota)
+        is distinct from
+        (new.key_hash, new.owner, new.is_active, new.expires_at, new.revoked_at, new.scopes, new.plan,
+         new.rate_per_second, new.burst, new.monthly_quota))
+  execute function notify_api_keys_changed();
+
+create trigger api_keys_changed_delete
+  after delete on api_keys
+  for each row
+  execute function notify_api_keys_changed();
@@ -0,0 +1,4 @@
+drop index if exists idx_api_keys_key_hash_version;
+
+alter table api_keys
+  drop column if exists key_hash_version;
@@ -0,0 +1,6 @@
+-- какой версией ENCODING_KEY посчитан key_hash; до версий секрет был один — версия 1
+alter table api_keys
+  add column if not exists key_hash_version integer not null default 1;
+
+create index if not exists idx_api_keys_key_hash_version
+  on api_keys (key_hash_version);
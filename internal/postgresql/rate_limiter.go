@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service-currency/internal"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimiter хранит бакеты и месячные счётчики в БД, общие для всех реплик.
+// Строка бакета блокируется на время списания, поэтому запросы одного ключа идут по очереди.
+type RateLimiter struct {
+	pool *pgxpool.Pool
+}
+
+func NewRateLimiter(pool *pgxpool.Pool) *RateLimiter {
+	return &RateLimiter{pool: pool}
+}
+
+// errDenied откатывает транзакцию списания, когда запрос не пропущен.
+var errDenied = errors.New("rate limit denied")
+
+func (l *RateLimiter) Allow(ctx context.Context, keyID int64, limits internal.Limits, now time.Time) (internal.RateDecision, error) {
+	month, reset := internal.QuotaMonth(now)
+	d := internal.RateDecision{QuotaReset: reset}
+
+	err := pgx.BeginFunc(ctx, l.pool, func(tx pgx.Tx) error {
+		var bucket internal.TokenBucket
+		if limits.RatePerSecond > 0 {
+			full := internal.NewTokenBucket(limits, now)
+			err := tx.QueryRow(ctx, `
+insert into api_key_rate_buckets (key_id, tokens, updated_at)
+values ($1, $2, $3)
+on conflict (key_id) do update set key_id = excluded.key_id
+returning tokens, updated_at;
+`, keyID, full.Tokens, full.UpdatedAt).Scan(&bucket.Tokens, &bucket.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("upsert api_key_rate_buckets: %w", err)
+			}
+
+			var retry time.Duration
+			var ok bool
+			bucket, retry, ok = bucket.Take(limits, now)
+			d.Remaining = int(bucket.Tokens)
+			if !ok {
+				d.Exceeded, d.RetryAfter = internal.LimitRate, retry
+				return errDenied
+			}
+		}
+
+		err := tx.QueryRow(ctx, `
+insert into api_key_monthly_usage (key_id, month, requests)
+values ($1, $2::date, 1)
+on conflict (key_id, month) do update set requests = api_key_monthly_usage.requests + 1
+returning requests;
+`, keyID, month).Scan(&d.QuotaUsed)
+		if err != nil {
+			return fmt.Errorf("upsert api_key_monthly_usage: %w", err)
+		}
+		if limits.MonthlyQuota > 0 && d.QuotaUsed > limits.MonthlyQuota {
+			d.QuotaUsed--
+			d.Exceeded, d.RetryAfter = internal.LimitQuota, reset.Sub(now)
+			return errDenied
+		}
+
+		if limits.RatePerSecond > 0 {
+			_, err = tx.Exec(ctx, `
+update api_key_rate_buckets
+set tokens = $2, updated_at = $3
+where key_id = $1;
+`, keyID, bucket.Tokens, bucket.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("update api_key_rate_buckets: %w", err)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errDenied) {
+		return d, nil
+	}
+	if err != nil {
+		return internal.RateDecision{}, err
+	}
+
+	d.Allowed = true
+	return d, nil
+}
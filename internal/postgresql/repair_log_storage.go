@@ -0,0 +1,39 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"service-currency/internal"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RepairLogStorage struct {
+	pgpool *pgxpool.Pool
+}
+
+func NewRepairLogStorage(pgpool *pgxpool.Pool) *RepairLogStorage {
+	return &RepairLogStorage{pgpool: pgpool}
+}
+
+func (s *RepairLogStorage) InsertRepair(ctx context.Context, repair internal.RateRepair) error {
+	quotes := make([]string, len(repair.Quotes))
+	for i, q := range repair.Quotes {
+		quotes[i] = q.String()
+	}
+
+	var errText *string
+	if repair.Err != nil {
+		e := repair.Err.Error()
+		errText = &e
+	}
+
+	_, err := s.pgpool.Exec(ctx, `
+insert into rate_repair_log (base_ccy, as_of_date, quotes, error)
+values ($1, $2::date, $3::text[], $4);
+`, repair.Base.String(), toDBDate(repair.Date), quotes, errText)
+	if err != nil {
+		return fmt.Errorf("insert rate_repair_log: %w", err)
+	}
+	return nil
+}
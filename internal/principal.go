@@ -0,0 +1,30 @@
+package internal
+
+import "context"
+
+// Principal — кто делает запрос: ключ, прошедший проверку.
+type Principal struct {
+	KeyID  int64
+	Owner  string
+	Scopes []string
+	Plan   string
+	// Limits — собственные ограничения ключа; nil — по тарифу Plan, см. LimitPlans.
+	Limits *Limits
+}
+
+func (k APIKey) Principal() Principal {
+	return Principal{KeyID: k.ID, Owner: k.Owner, Scopes: k.Scopes, Plan: k.Plan, Limits: k.Limits}
+}
+
+type principalCtxKey struct{}
+
+// WithPrincipal кладёт p в контекст запроса; так делает middleware.APIKeyAuth.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFromContext возвращает того, кто делает запрос. ok=false — запрос без ключа.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,174 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limits — ограничения ключа: токен-бакет и квота запросов на календарный месяц (UTC).
+// Ноль в поле — без этого ограничения.
+type Limits struct {
+	RatePerSecond float64 `json:"rate_per_second"`
+	Burst         int     `json:"burst"`
+	MonthlyQuota  int64   `json:"monthly_quota"`
+}
+
+func (l Limits) Unlimited() bool {
+	return l.RatePerSecond <= 0 && l.MonthlyQuota <= 0
+}
+
+// CheckLimits проверяет собственные ограничения ключа; nil допустим.
+func CheckLimits(l *Limits) error {
+	if l == nil {
+		return nil
+	}
+	if l.RatePerSecond < 0 || l.Burst < 0 || l.MonthlyQuota < 0 {
+		return errors.New("limits must not be negative")
+	}
+	if l.RatePerSecond > 0 && l.Burst < 1 {
+		return errors.New("burst must be at least 1 when rate_per_second is set")
+	}
+	return nil
+}
+
+// DefaultPlan — тариф ключа, если в LimitPlans нет его собственного.
+const DefaultPlan = "default"
+
+// LimitPlans — ограничения по тарифам, ключ — APIKey.Plan.
+type LimitPlans map[string]Limits
+
+// Check проверяет, что тариф plan задан в конфиге.
+func (p LimitPlans) Check(plan string) error {
+	if _, ok := p[plan]; !ok {
+		return fmt.Errorf("unknown plan %q", plan)
+	}
+	return nil
+}
+
+// For — ограничения ключа: свои, если заданы, иначе его тарифа, иначе DefaultPlan.
+func (p LimitPlans) For(principal Principal) Limits {
+	if principal.Limits != nil {
+		return *principal.Limits
+	}
+	if l, ok := p[principal.Plan]; ok {
+		return l
+	}
+	return p[DefaultPlan]
+}
+
+// LimitKind — какое ограничение не пустило запрос.
+type LimitKind string
+
+const (
+	LimitRate  LimitKind = "rate"
+	LimitQuota LimitKind = "quota"
+)
+
+// RateDecision — результат списания запроса.
+type RateDecision struct {
+	Allowed  bool
+	Exceeded LimitKind // заполнено, только если Allowed == false
+	// Remaining — целых токенов в бакете после запроса.
+	Remaining int
+	// RetryAfter — через сколько повторить; только если Allowed == false.
+	RetryAfter time.Duration
+	// QuotaUsed — запросов за текущий месяц, включая этот, если он пропущен.
+	QuotaUsed  int64
+	QuotaReset time.Time
+}
+
+// RateLimiter списывает запросы ключей. Отказ — не ошибка: его описывает RateDecision.
+type RateLimiter interface {
+	Allow(ctx context.Context, keyID int64, limits Limits, now time.Time) (RateDecision, error)
+}
+
+// TokenBucket — состояние бакета ключа. Бакет наполняется со скоростью RatePerSecond до Burst.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewTokenBucket — полный бакет.
+func NewTokenBucket(l Limits, now time.Time) TokenBucket {
+	return TokenBucket{Tokens: float64(l.Burst), UpdatedAt: now}
+}
+
+// Take пополняет бакет на момент now и берёт из него токен. Если токена нет,
+// retryAfter — когда он появится; состояние бакета всё равно сдвигается на now.
+func (b TokenBucket) Take(l Limits, now time.Time) (next TokenBucket, retryAfter time.Duration, ok bool) {
+	elapsed := max(now.Sub(b.UpdatedAt).Seconds(), 0)
+	tokens := min(float64(l.Burst), b.Tokens+elapsed*l.RatePerSecond)
+	if tokens >= 1 {
+		return TokenBucket{Tokens: tokens - 1, UpdatedAt: now}, 0, true
+	}
+
+	wait := (1 - tokens) / l.RatePerSecond
+	return TokenBucket{Tokens: tokens, UpdatedAt: now}, time.Duration(math.Ceil(wait * float64(time.Second))), false
+}
+
+// QuotaMonth — начало месяца квоты, в который попадает now, и начало следующего.
+func QuotaMonth(now time.Time) (start, reset time.Time) {
+	now = now.UTC()
+	start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// MemoryRateLimiter держит бакеты и счётчики в памяти процесса: годится для одного экземпляра,
+// при нескольких репликах — postgresql.RateLimiter.
+type MemoryRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[int64]TokenBucket
+	usage   map[int64]monthUsage
+}
+
+type monthUsage struct {
+	month    time.Time
+	requests int64
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets: make(map[int64]TokenBucket),
+		usage:   make(map[int64]monthUsage),
+	}
+}
+
+func (m *MemoryRateLimiter) Allow(_ context.Context, keyID int64, limits Limits, now time.Time) (RateDecision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	month, reset := QuotaMonth(now)
+	used := m.usage[keyID]
+	if !used.month.Equal(month) {
+		used = monthUsage{month: month}
+	}
+	d := RateDecision{QuotaUsed: used.requests, QuotaReset: reset}
+
+	if limits.MonthlyQuota > 0 && used.requests >= limits.MonthlyQuota {
+		d.Exceeded, d.RetryAfter = LimitQuota, reset.Sub(now)
+		return d, nil
+	}
+
+	if limits.RatePerSecond > 0 {
+		bucket, ok := m.buckets[keyID]
+		if !ok {
+			bucket = NewTokenBucket(limits, now)
+		}
+		bucket, retry, ok := bucket.Take(limits, now)
+		m.buckets[keyID] = bucket
+		d.Remaining = int(bucket.Tokens)
+		if !ok {
+			d.Exceeded, d.RetryAfter = LimitRate, retry
+			return d, nil
+		}
+	}
+
+	used.requests++
+	m.usage[keyID] = used
+	d.Allowed, d.QuotaUsed = true, used.requests
+	return d, nil
+}
@@ -0,0 +1,77 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimiter_TokenBucket(t *testing.T) {
+	limiter := internal.NewMemoryRateLimiter()
+	limits := internal.Limits{RatePerSecond: 2, Burst: 3}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for i := range 3 {
+		d, err := limiter.Allow(context.Background(), 1, limits, now)
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+		assert.Equal(t, 2-i, d.Remaining)
+	}
+
+	d, err := limiter.Allow(context.Background(), 1, limits, now)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, internal.LimitRate, d.Exceeded)
+	assert.Equal(t, 500*time.Millisecond, d.RetryAfter)
+
+	// у другого ключа свой бакет
+	d, err = limiter.Allow(context.Background(), 2, limits, now)
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+
+	d, err = limiter.Allow(context.Background(), 1, limits, now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+}
+
+func TestMemoryRateLimiter_MonthlyQuota(t *testing.T) {
+	limiter := internal.NewMemoryRateLimiter()
+	limits := internal.Limits{MonthlyQuota: 2}
+	now := time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC)
+
+	for range 2 {
+		d, err := limiter.Allow(context.Background(), 1, limits, now)
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+	}
+
+	d, err := limiter.Allow(context.Background(), 1, limits, now)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, internal.LimitQuota, d.Exceeded)
+	assert.Equal(t, int64(2), d.QuotaUsed)
+	assert.Equal(t, time.Hour, d.RetryAfter)
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), d.QuotaReset)
+
+	// с нового месяца квота снова полная
+	d, err = limiter.Allow(context.Background(), 1, limits, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, int64(1), d.QuotaUsed)
+}
+
+func TestLimitPlans_For(t *testing.T) {
+	plans := internal.LimitPlans{
+		internal.DefaultPlan: {RatePerSecond: 1, Burst: 1},
+		"pro":                {RatePerSecond: 10, Burst: 20},
+	}
+	own := internal.Limits{MonthlyQuota: 5}
+
+	assert.Equal(t, plans["pro"], plans.For(internal.Principal{Plan: "pro"}))
+	assert.Equal(t, plans[internal.DefaultPlan], plans.For(internal.Principal{Plan: "legacy"}))
+	assert.Equal(t, own, plans.For(internal.Principal{Plan: "pro", Limits: &own}))
+}
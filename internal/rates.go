@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -15,6 +16,31 @@ type LatestRatesResponse struct {
 	Rates map[string]string `json:"rates"`
 }
 
+// ParseRates разбирает ответ провайдера: проверяет коды валют и переводит курсы в decimal.
+func (r *LatestRatesResponse) ParseRates() (CurrencyCode, map[CurrencyCode]decimal.Decimal, error) {
+	baseCCY, err := NewCurrencyCode(r.Base)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid base %q: %w", r.Base, err)
+	}
+
+	typedRates := make(map[CurrencyCode]decimal.Decimal, len(r.Rates))
+	for quoteStr, rateStr := range r.Rates {
+		quote, err := NewCurrencyCode(quoteStr)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid quote %q: %w", quoteStr, err)
+		}
+
+		rateStr = strings.TrimSpace(rateStr)
+		rate, err := decimal.NewFromString(rateStr)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid rate %s/%s=%q: %w", baseCCY, quote, rateStr, err)
+		}
+
+		typedRates[quote] = rate
+	}
+	return baseCCY, typedRates, nil
+}
+
 type CurrencyLatestRate struct {
 	BaseCCY   CurrencyCode
 	QuoteCCY  CurrencyCode
@@ -24,11 +50,60 @@ type CurrencyLatestRate struct {
 }
 
 type Storage interface {
+	// GetLatest возвращает последний известный курс для каждой quote (все quotes base, если список пуст).
 	GetLatest(ctx context.Context, base CurrencyCode, quotes []CurrencyCode) ([]CurrencyLatestRate, error)
+	// GetOnDate возвращает курсы, сохранённые ровно на дату date.
+	GetOnDate(ctx context.Context, base CurrencyCode, quotes []CurrencyCode, date Date) ([]CurrencyLatestRate, error)
+	// GetRange возвращает все сохранённые курсы за [from, to], по quote и дате по возрастанию.
+	GetRange(ctx context.Context, base CurrencyCode, quotes []CurrencyCode, from, to Date) ([]CurrencyLatestRate, error)
+	// GetLatestCommon возвращает курсы всех quotes на последнюю дату, на которую сохранены все они сразу.
+	GetLatestCommon(ctx context.Context, base CurrencyCode, quotes []CurrencyCode) ([]CurrencyLatestRate, error)
+	// GetLegs читает набор курсов base->quote одним запросом: последние (RateLeg.Date == nil) и на даты.
+	GetLegs(ctx context.Context, base CurrencyCode, legs []RateLeg) ([]CurrencyLatestRate, error)
+	// GetAllLatest возвращает последний курс для каждой сохранённой пары с любой базой.
+	GetAllLatest(ctx context.Context) ([]CurrencyLatestRate, error)
+	// GetAllOnDate возвращает курсы всех пар, сохранённые ровно на дату date.
+	GetAllOnDate(ctx context.Context, date Date) ([]CurrencyLatestRate, error)
 }
 
+// RateLeg — курс base->Quote: последний, если Date == nil, иначе ровно на дату.
+type RateLeg struct {
+	Quote CurrencyCode
+	Date  *Date
+}
+
+// ConversionMode определяет, как RateConverter собирает курс пары из сохранённых курсов.
+type ConversionMode string
+
+const (
+	// ConversionPivot — через одну опорную валюту: pivot->base и pivot->quote.
+	ConversionPivot ConversionMode = "pivot"
+	// ConversionPath — поиск цепочки по всем сохранённым парам с любой базой,
+	// с откатом на ConversionPivot, если цепочки нет.
+	ConversionPath ConversionMode = "path"
+)
+
+// MixedDatesPolicy решает, что делать, если ноги кросс-курса оказались на разные даты
+// (например, по одной валюте последнее обновление не пришло).
+type MixedDatesPolicy string
+
+const (
+	// MixedDatesAllow — считать по последним курсам ног и пометить ответ MixedDates.
+	MixedDatesAllow MixedDatesPolicy = "allow"
+	// MixedDatesReject — вернуть ErrMixedDates.
+	MixedDatesReject MixedDatesPolicy = "reject"
+	// MixedDatesCommon — взять последнюю дату, на которую есть все ноги.
+	MixedDatesCommon MixedDatesPolicy = "common"
+)
+
+var ErrMixedDates = errors.New("cross rate legs have different dates")
+
 type RateConverter struct {
-	storage Storage
+	storage    Storage
+	history    *HistoricalRates
+	pivot      CurrencyCode
+	mode       ConversionMode
+	mixedDates MixedDatesPolicy
 }
 
 type RatesClient interface {
@@ -40,70 +115,232 @@ type RatesClient interface {
 	) (*LatestRatesResponse, error)
 }
 
-func NewRateConverter(storage Storage) *RateConverter { return &RateConverter{storage: storage} }
+func NewRateConverter(storage Storage) *RateConverter {
+	return &RateConverter{storage: storage, pivot: RUB, mode: ConversionPivot, mixedDates: MixedDatesAllow}
+}
+
+// WithMixedDates задаёт политику для ног кросс-курса на разные даты (по умолчанию MixedDatesAllow).
+func (s *RateConverter) WithMixedDates(policy MixedDatesPolicy) *RateConverter {
+	s.mixedDates = policy
+	return s
+}
+
+// WithPivot задаёт опорную валюту — базу, в которой хранятся курсы (по умолчанию RUB).
+func (s *RateConverter) WithPivot(pivot CurrencyCode) *RateConverter {
+	s.pivot = pivot
+	return s
+}
+
+// WithMode задаёт способ сборки кросс-курса (по умолчанию ConversionPivot).
+func (s *RateConverter) WithMode(mode ConversionMode) *RateConverter {
+	s.mode = mode
+	return s
+}
+
+// WithHistory включает догрузку курсов на дату от провайдера, если в БД их нет.
+func (s *RateConverter) WithHistory(history *HistoricalRates) *RateConverter {
+	s.history = history
+	return s
+}
 
 type PairRate struct {
+	Base  CurrencyCode    `json:"base"`
+	Quote CurrencyCode    `json:"quote"`
+	Rate  decimal.Decimal `json:"rate"`
+	// Date — дата курса; у кросс-курса с ногами на разные даты — самая старая из них.
+	Date *Date `json:"date,omitempty"`
+	// Path — цепочка валют, если курс собран поиском пути (ConversionPath).
+	Path []CurrencyCode `json:"path,omitempty"`
+	// MixedDates и Legs заполняются, только если ноги кросс-курса на разные даты.
+	MixedDates bool      `json:"mixed_dates,omitempty"`
+	Legs       []PairLeg `json:"legs,omitempty"`
+}
+
+// PairLeg — один из курсов, из которых собран кросс-курс.
+type PairLeg struct {
 	Base  CurrencyCode    `json:"base"`
 	Quote CurrencyCode    `json:"quote"`
 	Rate  decimal.Decimal `json:"rate"`
 	Date  *Date           `json:"date,omitempty"`
 }
 
+// legsFunc возвращает курсы pivot->quote для всех quotes одним чтением,
+// чтобы ноги кросс-курса были из одного снимка данных.
+type legsFunc func(ctx context.Context, quotes []CurrencyCode) ([]CurrencyLatestRate, error)
+
 func (s *RateConverter) GetPairRate(ctx context.Context, base, quote CurrencyCode) (PairRate, error) {
+	err := checkPair(base, quote)
+	if err != nil {
+		return PairRate{}, err
+	}
+
+	if s.mode == ConversionPath {
+		pr, found, err := s.findPath(ctx, base, quote, s.storage.GetAllLatest)
+		if err != nil || found {
+			return pr, err
+		}
+	}
+	return s.pairRate(ctx, base, quote, s.getLatestLegs)
+}
+
+// GetPairRateOn считает курс пары на дату date по тем же правилам, что и GetPairRate.
+func (s *RateConverter) GetPairRateOn(ctx context.Context, base, quote CurrencyCode, date Date) (PairRate, error) {
+	if date.IsZero() {
+		return PairRate{}, errors.New("date is empty")
+	}
+	err := checkPair(base, quote)
+	if err != nil {
+		return PairRate{}, err
+	}
+
+	if s.mode == ConversionPath {
+		pr, found, err := s.findPath(ctx, base, quote, func(ctx context.Context) ([]CurrencyLatestRate, error) {
+			return s.storage.GetAllOnDate(ctx, date)
+		})
+		if err != nil || found {
+			return pr, err
+		}
+	}
+	return s.pairRate(ctx, base, quote, func(ctx context.Context, quotes []CurrencyCode) ([]CurrencyLatestRate, error) {
+		return s.getLegsOn(ctx, quotes, date)
+	})
+}
+
+func checkPair(base, quote CurrencyCode) error {
 	if !base.IsSupported() {
-		return PairRate{}, errors.New("unsupported currency")
+		return errors.New("unsupported currency")
 	}
 	if !quote.IsSupported() {
-		return PairRate{}, errors.New("unsupported currency")
+		return errors.New("unsupported currency")
+	}
+	return nil
+}
+
+func (s *RateConverter) pairRate(ctx context.Context, base, quote CurrencyCode, legs legsFunc) (PairRate, error) {
+	pivot := s.pivot
+
+	quotes := s.legQuotes(base, quote)
+	if len(quotes) == 0 {
+		return PairRate{Base: base, Quote: quote, Rate: decimal.NewFromInt(1)}, nil
 	}
 
-	// 1) RUB -> Any
-	if base == RUB {
-		r, err := s.getLatestRUBTo(ctx, quote)
-		if err != nil {
-			return PairRate{}, err
+	rows, err := legs(ctx, quotes)
+	if err != nil {
+		return PairRate{}, err
+	}
+	byQuote := make(map[CurrencyCode]CurrencyLatestRate, len(rows))
+	for _, r := range rows {
+		byQuote[r.QuoteCCY] = r
+	}
+	for _, q := range quotes {
+		if _, ok := byQuote[q]; !ok {
+			return PairRate{}, errors.New("rate not available")
 		}
+	}
+
+	// 1) pivot -> Any
+	if base == pivot {
+		r := byQuote[quote]
 		return PairRate{Base: base, Quote: quote, Rate: r.Rate, Date: r.AsOfDate}, nil
 	}
 
-	// 2) Any -> RUB
-	if quote == RUB {
-		r, err := s.getLatestRUBTo(ctx, base) // RUB->base
-		if err != nil {
-			return PairRate{}, err
-		}
+	// 2) Any -> pivot
+	if quote == pivot {
+		r := byQuote[base] // pivot->base
 		if r.Rate.IsZero() {
-			return PairRate{}, fmt.Errorf("rate %s/%s is zero, cannot invert", RUB, base)
+			return PairRate{}, fmt.Errorf("rate %s/%s is zero, cannot invert", pivot, base)
 		}
 
-		inv := decimal.NewFromInt(1).Div(r.Rate) // base->RUB
+		inv := decimal.NewFromInt(1).Div(r.Rate) // base->pivot
 		return PairRate{Base: base, Quote: quote, Rate: inv, Date: r.AsOfDate}, nil
 	}
 
-	// 3) Any -> Any (через RUB)
-	rBase, err := s.getLatestRUBTo(ctx, base)
-	if err != nil {
-		return PairRate{}, err
-	}
-	rQuote, err := s.getLatestRUBTo(ctx, quote)
-	if err != nil {
-		return PairRate{}, err
-	}
+	// 3) Any -> Any (через pivot)
+	rBase, rQuote := byQuote[base], byQuote[quote]
 	if rBase.Rate.IsZero() {
-		return PairRate{}, fmt.Errorf("rate %s/%s is zero, cannot divide", RUB, base)
+		return PairRate{}, fmt.Errorf("rate %s/%s is zero, cannot divide", pivot, base)
 	}
 
 	cross := rQuote.Rate.Div(rBase.Rate) // base -> quote
-	return PairRate{Base: base, Quote: quote, Rate: cross, Date: rBase.AsOfDate}, nil
+	out := PairRate{Base: base, Quote: quote, Rate: cross, Date: rBase.AsOfDate}
+
+	legDates := []*Date{rBase.AsOfDate, rQuote.AsOfDate}
+	if !sameDates(legDates) {
+		if s.mixedDates == MixedDatesReject {
+			return PairRate{}, fmt.Errorf("%w: %s/%s @%s, %s/%s @%s", ErrMixedDates,
+				pivot, base, formatDate(rBase.AsOfDate), pivot, quote, formatDate(rQuote.AsOfDate))
+		}
+		out.Date = oldestDate(legDates)
+		out.MixedDates = true
+		out.Legs = []PairLeg{
+			{Base: pivot, Quote: base, Rate: rBase.Rate, Date: rBase.AsOfDate},
+			{Base: pivot, Quote: quote, Rate: rQuote.Rate, Date: rQuote.AsOfDate},
+		}
+	}
+	return out, nil
+}
+
+// legQuotes — валюты, курсы pivot->X которых нужны для пары base->quote.
+func (s *RateConverter) legQuotes(base, quote CurrencyCode) []CurrencyCode {
+	quotes := make([]CurrencyCode, 0, 2)
+	if base != s.pivot {
+		quotes = append(quotes, base)
+	}
+	if quote != s.pivot && quote != base {
+		quotes = append(quotes, quote)
+	}
+	return quotes
+}
+
+func (s *RateConverter) getLatestLegs(ctx context.Context, quotes []CurrencyCode) ([]CurrencyLatestRate, error) {
+	var rows []CurrencyLatestRate
+	var err error
+	if s.mixedDates == MixedDatesCommon && len(quotes) > 1 {
+		rows, err = s.storage.GetLatestCommon(ctx, s.pivot, quotes)
+	} else {
+		rows, err = s.storage.GetLatest(ctx, s.pivot, quotes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest %s/%s: %w", s.pivot, joinCodes(quotes), err)
+	}
+	return rows, nil
 }
 
-func (s *RateConverter) getLatestRUBTo(ctx context.Context, quote CurrencyCode) (CurrencyLatestRate, error) {
-	rows, err := s.storage.GetLatest(ctx, RUB, []CurrencyCode{quote})
+func (s *RateConverter) getLegsOn(ctx context.Context, quotes []CurrencyCode, date Date) ([]CurrencyLatestRate, error) {
+	if s.history == nil {
+		rows, err := s.storage.GetOnDate(ctx, s.pivot, quotes, date)
+		if err != nil {
+			return nil, fmt.Errorf("get %s/%s @%s: %w", s.pivot, joinCodes(quotes), date.Format(dateLayout), err)
+		}
+		return rows, nil
+	}
+
+	// HistoricalRates сам сначала смотрит в БД
+	resp, err := s.history.Get(ctx, date, s.pivot, quotes)
 	if err != nil {
-		return CurrencyLatestRate{}, fmt.Errorf("get latest %s/%s: %w", RUB, quote, err)
+		return nil, fmt.Errorf("get %s/%s @%s: %w", s.pivot, joinCodes(quotes), date.Format(dateLayout), err)
+	}
+
+	asOf := resp.Date
+	rows := make([]CurrencyLatestRate, 0, len(quotes))
+	for _, q := range quotes {
+		rateStr, ok := resp.Rates[q.String()]
+		if !ok {
+			continue
+		}
+		rate, err := decimal.NewFromString(rateStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse rate %s/%s=%q: %w", s.pivot, q, rateStr, err)
+		}
+		rows = append(rows, CurrencyLatestRate{BaseCCY: s.pivot, QuoteCCY: q, Rate: rate, AsOfDate: &asOf})
 	}
-	if len(rows) == 0 {
-		return CurrencyLatestRate{}, errors.New("rate not available")
+	return rows, nil
+}
+
+func joinCodes(codes []CurrencyCode) string {
+	parts := make([]string, len(codes))
+	for i, c := range codes {
+		parts[i] = c.String()
 	}
-	return rows[0], nil
+	return strings.Join(parts, ",")
 }
@@ -72,15 +72,9 @@ func TestRateConverter_GetPairRate_USDToEUR(t *testing.T) {
 	rateEUR, _ := decimal.NewFromString("0.0095")
 
 	mockStorage.EXPECT().
-		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD}).
+		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD, internal.EUR}).
 		Return([]internal.CurrencyLatestRate{
 			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: rateUSD, FetchedAt: time.Now()},
-		}, nil).
-		Once()
-
-	mockStorage.EXPECT().
-		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.EUR}).
-		Return([]internal.CurrencyLatestRate{
 			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: rateEUR, FetchedAt: time.Now()},
 		}, nil).
 		Once()
@@ -119,3 +113,225 @@ func TestRateConverter_GetPairRate_UnsupportedCurrency(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported currency")
 }
+
+func TestRateConverter_GetPairRateOn_USDToEUR(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	date := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+	rateUSD, _ := decimal.NewFromString("0.01")
+	rateEUR, _ := decimal.NewFromString("0.0095")
+
+	mockStorage.EXPECT().
+		GetOnDate(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD, internal.EUR}, date).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: rateUSD, AsOfDate: &date},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: rateEUR, AsOfDate: &date},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	result, err := converter.GetPairRateOn(context.Background(), internal.USD, internal.EUR, date)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0.9500", result.Rate.StringFixed(4))
+	assert.Equal(t, &date, result.Date)
+}
+
+func TestRateConverter_GetPairRateOn_FetchesMissingFromHistory(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+
+	date := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.EXPECT().
+		GetOnDate(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD}, date).
+		Return(nil, nil).
+		Once()
+
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, date, internal.RUB, []internal.CurrencyCode{internal.USD}).
+		Return(&internal.LatestRatesResponse{Date: date, Base: "RUB", Rates: map[string]string{"USD": "0.01"}}, nil).
+		Once()
+
+	mockWriter.EXPECT().
+		UpsertRatesMap(testifymock.Anything, internal.RUB, date, testifymock.Anything).
+		Return(nil).
+		Once()
+
+	history := internal.NewHistoricalRates(mockStorage, mockWriter, mockClient)
+	converter := internal.NewRateConverter(mockStorage).WithHistory(history)
+	result, err := converter.GetPairRateOn(context.Background(), internal.USD, internal.RUB, date)
+
+	require.NoError(t, err)
+	assert.Equal(t, "100.0000", result.Rate.StringFixed(4))
+	assert.Equal(t, date, *result.Date)
+}
+
+func TestRateConverter_Convert_RoundsToMinorUnits(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	rateJPY, _ := decimal.NewFromString("1.6543")
+	mockStorage.EXPECT().
+		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.JPY}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.JPY, Rate: rateJPY, FetchedAt: time.Now()},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	result, err := converter.Convert(context.Background(), internal.RUB, internal.JPY, decimal.RequireFromString("123.45"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "204", result.Result.String()) // 204.2233... -> JPY без дробной части
+	assert.Equal(t, "1.6543", result.Rate.String())
+	assert.Equal(t, "123.45", result.Amount.String())
+}
+
+func TestRateConverter_Convert_NegativeAmount(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	converter := internal.NewRateConverter(mockStorage)
+
+	_, err := converter.Convert(context.Background(), internal.RUB, internal.USD, decimal.RequireFromString("-1"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "amount must not be negative")
+}
+
+func TestRateConverter_GetPairRate_CustomPivot(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	mockStorage.EXPECT().
+		GetLatest(testifymock.Anything, internal.USD, []internal.CurrencyCode{internal.EUR}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.USD, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.8"), FetchedAt: time.Now()},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage).WithPivot(internal.USD)
+	result, err := converter.GetPairRate(context.Background(), internal.EUR, internal.USD)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.2500", result.Rate.StringFixed(4))
+}
+
+func TestRateConverter_GetPairRate_PathPrefersShortestThenFreshest(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	old := internal.Date{Time: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)}
+	fresh := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+	gbp := internal.CurrencyCode("GBP")
+	chf := internal.CurrencyCode("CHF")
+
+	mockStorage.EXPECT().
+		GetAllLatest(testifymock.Anything).
+		Return([]internal.CurrencyLatestRate{
+			// JPY->USD->EUR: два шага, обе ноги свежие
+			{BaseCCY: internal.USD, QuoteCCY: internal.JPY, Rate: decimal.RequireFromString("150"), AsOfDate: &fresh},
+			{BaseCCY: internal.USD, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.9"), AsOfDate: &fresh},
+			// JPY->GBP->EUR: тоже два шага, но одна нога старая
+			{BaseCCY: gbp, QuoteCCY: internal.JPY, Rate: decimal.RequireFromString("190"), AsOfDate: &old},
+			{BaseCCY: gbp, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("1.2"), AsOfDate: &fresh},
+			// JPY->CHF->USD->EUR: свежий, но длиннее
+			{BaseCCY: chf, QuoteCCY: internal.JPY, Rate: decimal.RequireFromString("170"), AsOfDate: &fresh},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage).WithMode(internal.ConversionPath)
+	result, err := converter.GetPairRate(context.Background(), internal.JPY, internal.EUR)
+
+	require.NoError(t, err)
+	assert.Equal(t, []internal.CurrencyCode{internal.JPY, internal.USD, internal.EUR}, result.Path)
+	assert.Equal(t, "0.006000", result.Rate.StringFixed(6)) // 1/150 * 0.9
+	assert.Equal(t, fresh, *result.Date)
+}
+
+func TestRateConverter_GetPairRate_PathFallsBackToPivot(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	mockStorage.EXPECT().
+		GetAllLatest(testifymock.Anything).
+		Return(nil, nil).
+		Once()
+
+	mockStorage.EXPECT().
+		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), FetchedAt: time.Now()},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage).WithMode(internal.ConversionPath)
+	result, err := converter.GetPairRate(context.Background(), internal.RUB, internal.USD)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Path)
+	assert.Equal(t, "0.0100", result.Rate.StringFixed(4))
+}
+
+func TestRateConverter_GetPairRate_MixedDatesAllowed(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	older := internal.Date{Time: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)}
+	newer := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.EXPECT().
+		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD, internal.EUR}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &newer},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &older},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	result, err := converter.GetPairRate(context.Background(), internal.USD, internal.EUR)
+
+	require.NoError(t, err)
+	assert.True(t, result.MixedDates)
+	assert.Equal(t, older, *result.Date)
+	require.Len(t, result.Legs, 2)
+	assert.Equal(t, newer, *result.Legs[0].Date)
+	assert.Equal(t, older, *result.Legs[1].Date)
+}
+
+func TestRateConverter_GetPairRate_MixedDatesRejected(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	older := internal.Date{Time: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)}
+	newer := internal.Date{Time: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.EXPECT().
+		GetLatest(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD, internal.EUR}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &newer},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &older},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage).WithMixedDates(internal.MixedDatesReject)
+	_, err := converter.GetPairRate(context.Background(), internal.USD, internal.EUR)
+
+	require.ErrorIs(t, err, internal.ErrMixedDates)
+}
+
+func TestRateConverter_GetPairRate_MixedDatesCommon(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	date := internal.Date{Time: time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)}
+
+	mockStorage.EXPECT().
+		GetLatestCommon(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD, internal.EUR}).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &date},
+			{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &date},
+		}, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage).WithMixedDates(internal.MixedDatesCommon)
+	result, err := converter.GetPairRate(context.Background(), internal.USD, internal.EUR)
+
+	require.NoError(t, err)
+	assert.False(t, result.MixedDates)
+	assert.Equal(t, date, *result.Date)
+	assert.Equal(t, "0.9500", result.Rate.StringFixed(4))
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultReconcileDays — за сколько прошедших дней проверять историю.
+const defaultReconcileDays = 30
+
+// RateRepair — запись о починке: за день Date не хватало курсов Quotes.
+// Err == nil — курсы загружены, иначе загрузить не удалось и день будет проверен снова.
+type RateRepair struct {
+	Base   CurrencyCode
+	Date   Date
+	Quotes []CurrencyCode
+	Err    error
+}
+
+type RepairLogStorage interface {
+	InsertRepair(ctx context.Context, repair RateRepair) error
+}
+
+// Reconciler ищет в сохранённой истории рабочие дни, за которые нет курсов
+// (например, упал плановый запуск), и догружает их через HistoricalRates.
+type Reconciler struct {
+	storage Storage
+	history *HistoricalRates
+	log     RepairLogStorage
+	days    int
+	now     func() time.Time
+}
+
+func NewReconciler(storage Storage, history *HistoricalRates, log RepairLogStorage) *Reconciler {
+	return &Reconciler{storage: storage, history: history, log: log, days: defaultReconcileDays, now: time.Now}
+}
+
+// WithDays задаёт глубину проверки в днях.
+func (r *Reconciler) WithDays(n int) *Reconciler {
+	if n > 0 {
+		r.days = n
+	}
+	return r
+}
+
+// WithClock задаёт источник текущего времени; по нему и его часовому поясу считается «сегодня».
+func (r *Reconciler) WithClock(now func() time.Time) *Reconciler {
+	r.now = now
+	return r
+}
+
+// Run проверяет дни с понедельника по пятницу за последние days дней, не считая сегодняшнего:
+// сегодняшний курс ещё может прийти по расписанию. Каждая попытка пишется в журнал.
+func (r *Reconciler) Run(ctx context.Context, base CurrencyCode, symbols []CurrencyCode) ([]RateRepair, error) {
+	if len(symbols) == 0 {
+		return nil, errors.New("symbols are empty")
+	}
+
+	now := r.now()
+	today := Date{Time: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)}
+	from, to := Date{Time: today.AddDate(0, 0, -r.days)}, Date{Time: today.AddDate(0, 0, -1)}
+
+	missing, err := missingSymbols(ctx, r.storage, base, symbols, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var repairs []RateRepair
+	for d := from; !d.After(to.Time); d = nextDay(d) {
+		quotes := missing[d.Format(dateLayout)]
+		if len(quotes) == 0 || !isBusinessDay(d) {
+			continue
+		}
+
+		repair := RateRepair{Base: base, Date: d, Quotes: quotes}
+		_, repair.Err = r.history.Get(ctx, d, base, quotes)
+		if ctx.Err() != nil {
+			return repairs, ctx.Err()
+		}
+
+		err = r.log.InsertRepair(ctx, repair)
+		if err != nil {
+			return repairs, fmt.Errorf("log repair %s @%s: %w", base, d.Format(dateLayout), err)
+		}
+		repairs = append(repairs, repair)
+	}
+	return repairs, nil
+}
+
+// RunAndLog — Run для планировщика: итог и ошибки уходят в лог.
+func (r *Reconciler) RunAndLog(ctx context.Context, base CurrencyCode, symbols []CurrencyCode) {
+	repairs, err := r.Run(ctx, base, symbols)
+	if err != nil {
+		log.Printf("reconcile failed: %v", err)
+	}
+
+	failed := 0
+	for _, rp := range repairs {
+		if rp.Err != nil {
+			failed++
+			log.Printf("reconcile %s @%s %s failed: %v", rp.Base, rp.Date.Format(dateLayout), joinCodes(rp.Quotes), rp.Err)
+		}
+	}
+	if len(repairs) > 0 {
+		log.Printf("reconcile %s: repaired=%d failed=%d", base, len(repairs)-failed, failed)
+	}
+}
+
+func isBusinessDay(d Date) bool {
+	wd := d.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
@@ -0,0 +1,63 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestReconciler_Run_RepairsMissingBusinessDays(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+	mockWriter := mock.NewMockRatesWriter(t)
+	mockClient := mock.NewMockRatesClient(t)
+	mockLog := mock.NewMockRepairLogStorage(t)
+
+	symbols := []internal.CurrencyCode{internal.USD}
+	// пн 6 января 2025: проверяются 2–5 января, из них рабочие — чт 2 и пт 3
+	now := time.Date(2025, 1, 6, 15, 0, 0, 0, time.UTC)
+	thu, fri := seriesDay(2), seriesDay(3)
+
+	mockStorage.EXPECT().
+		GetRange(testifymock.Anything, internal.RUB, symbols, seriesDay(2), seriesDay(5)).
+		Return([]internal.CurrencyLatestRate{
+			{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &thu},
+		}, nil).
+		Once()
+
+	mockStorage.EXPECT().
+		GetOnDate(testifymock.Anything, internal.RUB, symbols, fri).
+		Return(nil, nil).
+		Once()
+	mockClient.EXPECT().
+		HistoricalRates(testifymock.Anything, fri, internal.RUB, symbols).
+		Return(&internal.LatestRatesResponse{Date: fri, Base: "RUB", Rates: map[string]string{"USD": "0.0101"}}, nil).
+		Once()
+	mockWriter.EXPECT().
+		UpsertRatesMap(testifymock.Anything, internal.RUB, fri, testifymock.Anything).
+		Return(nil).
+		Once()
+
+	mockLog.EXPECT().
+		InsertRepair(testifymock.Anything, internal.RateRepair{Base: internal.RUB, Date: fri, Quotes: symbols}).
+		Return(nil).
+		Once()
+
+	history := internal.NewHistoricalRates(mockStorage, mockWriter, mockClient)
+	repairs, err := internal.NewReconciler(mockStorage, history, mockLog).
+		WithDays(4).
+		WithClock(func() time.Time { return now }).
+		Run(context.Background(), internal.RUB, symbols)
+
+	require.NoError(t, err)
+	require.Len(t, repairs, 1)
+	assert.Equal(t, fri, repairs[0].Date)
+	assert.NoError(t, repairs[0].Err)
+}
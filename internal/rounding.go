@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type RoundingMode string
+
+const (
+	// RoundHalfUp — половина округляется от нуля: 0.0105 -> 0.011.
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundBankers — половина округляется к чётной цифре: 0.0105 -> 0.010.
+	RoundBankers RoundingMode = "bankers"
+	// RoundTruncate — лишние цифры отбрасываются.
+	RoundTruncate RoundingMode = "truncate"
+)
+
+// Rounding — как округлять курс в ответе. Задаётся Precision (знаков после запятой)
+// или SignificantDigits; если нет ни того, ни другого, значение не округляется.
+type Rounding struct {
+	Mode              RoundingMode
+	Precision         *int32
+	SignificantDigits *int32
+}
+
+// Apply округляет d. RateConverter всегда отдаёт точное значение, округление — только на выдаче.
+func (r Rounding) Apply(d decimal.Decimal) decimal.Decimal {
+	var places int32
+	switch {
+	case r.Precision != nil:
+		places = *r.Precision
+	case r.SignificantDigits != nil:
+		if d.IsZero() {
+			return d
+		}
+		// позиция старшей значащей цифры: 123.4 -> 2, 0.0105 -> -2
+		abs := d.Abs()
+		msd := int32(len(abs.Coefficient().String())) + abs.Exponent() - 1
+		places = *r.SignificantDigits - 1 - msd
+	default:
+		return d
+	}
+
+	switch r.Mode {
+	case RoundBankers:
+		return d.RoundBank(places)
+	case RoundTruncate:
+		// Truncate не принимает отрицательную точность
+		return d.Shift(places).Truncate(0).Shift(-places)
+	default:
+		return d.Round(places)
+	}
+}
+
+// ParseRounding читает правило из параметров вида precision=4, significant_digits=6, rounding=bankers.
+// Отсутствующие параметры берутся из def.
+func ParseRounding(v url.Values, def Rounding) (Rounding, error) {
+	out := def
+
+	if m := strings.TrimSpace(v.Get("rounding")); m != "" {
+		mode := RoundingMode(strings.ToLower(m))
+		switch mode {
+		case RoundHalfUp, RoundBankers, RoundTruncate:
+			out.Mode = mode
+		default:
+			return Rounding{}, fmt.Errorf("invalid rounding %q, expected %s, %s or %s", m, RoundHalfUp, RoundBankers, RoundTruncate)
+		}
+	}
+
+	precision, err := parseDigits(v, "precision", 0, 20)
+	if err != nil {
+		return Rounding{}, err
+	}
+	significant, err := parseDigits(v, "significant_digits", 1, 30)
+	if err != nil {
+		return Rounding{}, err
+	}
+
+	switch {
+	case precision != nil && significant != nil:
+		return Rounding{}, errors.New("precision and significant_digits are mutually exclusive")
+	case precision != nil:
+		out.Precision, out.SignificantDigits = precision, nil
+	case significant != nil:
+		out.Precision, out.SignificantDigits = nil, significant
+	}
+	return out, nil
+}
+
+func parseDigits(v url.Values, key string, lo, hi int) (*int32, error) {
+	raw := strings.TrimSpace(v.Get(key))
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < lo || n > hi {
+		return nil, fmt.Errorf("invalid %s %q, expected integer in [%d, %d]", key, raw, lo, hi)
+	}
+	p := int32(n)
+	return &p, nil
+}
+
+// RoundingRules — серверные умолчания: правило для пары, если задано, иначе Default.
+type RoundingRules struct {
+	Default Rounding
+	Pairs   map[[2]CurrencyCode]Rounding
+}
+
+func (r RoundingRules) For(base, quote CurrencyCode) Rounding {
+	if rule, ok := r.Pairs[[2]CurrencyCode{base, quote}]; ok {
+		return rule
+	}
+	return r.Default
+}
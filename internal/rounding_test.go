@@ -0,0 +1,60 @@
+package internal_test
+
+import (
+	"net/url"
+	"service-currency/internal"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRounding_Apply(t *testing.T) {
+	two, three := int32(2), int32(3)
+
+	tests := []struct {
+		name     string
+		rounding internal.Rounding
+		in       string
+		want     string
+	}{
+		{"no rule keeps value", internal.Rounding{}, "0.0105", "0.0105"},
+		{"precision half up", internal.Rounding{Mode: internal.RoundHalfUp, Precision: &three}, "0.0105", "0.011"},
+		{"precision bankers", internal.Rounding{Mode: internal.RoundBankers, Precision: &three}, "0.0105", "0.01"},
+		{"precision truncate", internal.Rounding{Mode: internal.RoundTruncate, Precision: &two}, "95.239", "95.23"},
+		{"significant small", internal.Rounding{Mode: internal.RoundHalfUp, SignificantDigits: &two}, "0.010549", "0.011"},
+		{"significant large", internal.Rounding{Mode: internal.RoundHalfUp, SignificantDigits: &two}, "15432.1", "15000"},
+		{"significant truncate large", internal.Rounding{Mode: internal.RoundTruncate, SignificantDigits: &three}, "15482.1", "15400"},
+		{"significant db trailing zeros", internal.Rounding{Mode: internal.RoundHalfUp, SignificantDigits: &three}, "0.0105000000", "0.0105"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rounding.Apply(decimal.RequireFromString(tt.in))
+			assert.True(t, got.Equal(decimal.RequireFromString(tt.want)), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestParseRounding(t *testing.T) {
+	six := int32(6)
+	def := internal.Rounding{Mode: internal.RoundHalfUp, SignificantDigits: &six}
+
+	r, err := internal.ParseRounding(url.Values{"precision": {"4"}, "rounding": {"bankers"}}, def)
+	require.NoError(t, err)
+	assert.Equal(t, internal.RoundBankers, r.Mode)
+	require.NotNil(t, r.Precision)
+	assert.Equal(t, int32(4), *r.Precision)
+	assert.Nil(t, r.SignificantDigits)
+
+	r, err = internal.ParseRounding(url.Values{}, def)
+	require.NoError(t, err)
+	assert.Equal(t, def, r)
+
+	_, err = internal.ParseRounding(url.Values{"precision": {"2"}, "significant_digits": {"3"}}, def)
+	require.Error(t, err)
+
+	_, err = internal.ParseRounding(url.Values{"rounding": {"ceil"}}, def)
+	require.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Права ключа. Маршрут объявляет нужное право при регистрации.
+const (
+	// ScopeRatesLatest — текущие курсы: пара, пакет, матрица.
+	ScopeRatesLatest = "rates:latest"
+	// ScopeRatesHistorical — курсы на прошедшие даты, ряды и статистика; может стоить запроса к провайдеру.
+	ScopeRatesHistorical = "rates:historical"
+	// ScopeConvert — пересчёт сумм.
+	ScopeConvert = "convert"
+)
+
+// AllScopes — известные права в порядке вывода.
+var AllScopes = []string{ScopeRatesLatest, ScopeRatesHistorical, ScopeConvert}
+
+// DefaultScopes — права нового ключа, если при выпуске не указаны другие.
+var DefaultScopes = []string{ScopeRatesLatest, ScopeConvert}
+
+// CheckScopes проверяет, что все scopes известны.
+func CheckScopes(scopes []string) error {
+	for _, s := range scopes {
+		if !slices.Contains(AllScopes, s) {
+			return fmt.Errorf("unknown scope %q", s)
+		}
+	}
+	return nil
+}
+
+// HasScope — есть ли у ключа право scope.
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
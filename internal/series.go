@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+type FillMode string
+
+const (
+	// FillSkip — дни без курса (выходные, праздники) в ряд не попадают.
+	FillSkip FillMode = "skip"
+	// FillCarryForward — в дни без курса повторяется последний известный.
+	FillCarryForward FillMode = "carry_forward"
+)
+
+// seriesLookback — на сколько дней до from искать курс, чтобы заполнить начало ряда
+// при FillCarryForward: from может прийтись на длинные праздники.
+const seriesLookback = 10
+
+type SeriesPoint struct {
+	Date Date            `json:"date"`
+	Rate decimal.Decimal `json:"rate"`
+	// Filled — курс перенесён с более ранней даты хотя бы по одной ноге.
+	Filled bool `json:"filled,omitempty"`
+}
+
+type RateSeries struct {
+	Base   CurrencyCode  `json:"base"`
+	Quote  CurrencyCode  `json:"quote"`
+	From   Date          `json:"from"`
+	To     Date          `json:"to"`
+	Fill   FillMode      `json:"fill"`
+	Points []SeriesPoint `json:"points"`
+}
+
+// GetSeries строит ряд курса base->quote по дням [from, to] из сохранённой истории,
+// по одной точке на день. Ноги читаются одним запросом GetRange, провайдер не вызывается.
+// Ряд всегда собирается через опорную валюту, CONVERSION_MODE на него не влияет.
+func (s *RateConverter) GetSeries(ctx context.Context, base, quote CurrencyCode, from, to Date, fill FillMode) (RateSeries, error) {
+	if from.IsZero() || to.IsZero() {
+		return RateSeries{}, errors.New("date range is empty")
+	}
+	if to.Before(from.Time) {
+		return RateSeries{}, fmt.Errorf("invalid range: %s is after %s", from.Format(dateLayout), to.Format(dateLayout))
+	}
+	if fill != FillSkip && fill != FillCarryForward {
+		return RateSeries{}, fmt.Errorf("invalid fill %q, expected %s or %s", fill, FillSkip, FillCarryForward)
+	}
+	err := checkPair(base, quote)
+	if err != nil {
+		return RateSeries{}, err
+	}
+
+	out := RateSeries{Base: base, Quote: quote, From: from, To: to, Fill: fill, Points: []SeriesPoint{}}
+
+	quotes := s.legQuotes(base, quote)
+	if len(quotes) == 0 {
+		for d := from; !d.After(to.Time); d = nextDay(d) {
+			out.Points = append(out.Points, SeriesPoint{Date: d, Rate: decimal.NewFromInt(1)})
+		}
+		return out, nil
+	}
+
+	start := from
+	if fill == FillCarryForward {
+		start = Date{Time: from.AddDate(0, 0, -seriesLookback)}
+	}
+	rows, err := s.storage.GetRange(ctx, s.pivot, quotes, start, to)
+	if err != nil {
+		return RateSeries{}, fmt.Errorf("get range %s/%s: %w", s.pivot, joinCodes(quotes), err)
+	}
+
+	// ключ — строка даты: у time.Time из БД и из запроса может отличаться Location
+	byDay := make(map[CurrencyCode]map[string]CurrencyLatestRate, len(quotes))
+	for _, q := range quotes {
+		byDay[q] = make(map[string]CurrencyLatestRate)
+	}
+	for _, r := range rows {
+		if r.AsOfDate == nil {
+			continue
+		}
+		if m, ok := byDay[r.QuoteCCY]; ok {
+			m[r.AsOfDate.Format(dateLayout)] = r
+		}
+	}
+
+	last := make(map[CurrencyCode]CurrencyLatestRate, len(quotes))
+	for d := start; !d.After(to.Time); d = nextDay(d) {
+		key := d.Format(dateLayout)
+		legs := make([]CurrencyLatestRate, 0, len(quotes))
+		filled := false
+		for _, q := range quotes {
+			r, ok := byDay[q][key]
+			if ok {
+				last[q] = r
+			} else if fill == FillCarryForward {
+				r, ok = last[q]
+				filled = filled || ok
+			}
+			if ok {
+				legs = append(legs, r)
+			}
+		}
+		if d.Before(from.Time) || len(legs) < len(quotes) {
+			continue
+		}
+
+		// перенесённые ноги уже отмечены Filled — политику разных дат к ним не применяем
+		day := d
+		for i := range legs {
+			legs[i].AsOfDate = &day
+		}
+		pr, err := s.pairRate(ctx, base, quote, func(context.Context, []CurrencyCode) ([]CurrencyLatestRate, error) {
+			return legs, nil
+		})
+		if err != nil {
+			return RateSeries{}, fmt.Errorf("%s: %w", key, err)
+		}
+		out.Points = append(out.Points, SeriesPoint{Date: d, Rate: pr.Rate, Filled: filled})
+	}
+	return out, nil
+}
+
+func nextDay(d Date) Date {
+	return Date{Time: d.AddDate(0, 0, 1)}
+}
@@ -0,0 +1,69 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func seriesDay(d int) internal.Date {
+	return internal.Date{Time: time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)}
+}
+
+func seriesRows() []internal.CurrencyLatestRate {
+	d1, d3, d4 := seriesDay(1), seriesDay(3), seriesDay(4)
+	return []internal.CurrencyLatestRate{
+		{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.0095"), AsOfDate: &d1},
+		{BaseCCY: internal.RUB, QuoteCCY: internal.EUR, Rate: decimal.RequireFromString("0.009"), AsOfDate: &d3},
+		{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &d1},
+		{BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString("0.01"), AsOfDate: &d4},
+	}
+}
+
+func TestRateConverter_GetSeries_Skip(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	mockStorage.EXPECT().
+		GetRange(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD, internal.EUR}, seriesDay(1), seriesDay(4)).
+		Return(seriesRows(), nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	s, err := converter.GetSeries(context.Background(), internal.USD, internal.EUR, seriesDay(1), seriesDay(4), internal.FillSkip)
+
+	require.NoError(t, err)
+	require.Len(t, s.Points, 1)
+	assert.Equal(t, seriesDay(1), s.Points[0].Date)
+	assert.Equal(t, "0.9500", s.Points[0].Rate.StringFixed(4))
+}
+
+func TestRateConverter_GetSeries_CarryForward(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	lookback := internal.Date{Time: time.Date(2024, 12, 22, 0, 0, 0, 0, time.UTC)}
+	mockStorage.EXPECT().
+		GetRange(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD, internal.EUR}, lookback, seriesDay(4)).
+		Return(seriesRows(), nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	s, err := converter.GetSeries(context.Background(), internal.USD, internal.EUR, seriesDay(1), seriesDay(4), internal.FillCarryForward)
+
+	require.NoError(t, err)
+	require.Len(t, s.Points, 4)
+	assert.False(t, s.Points[0].Filled)
+	assert.True(t, s.Points[1].Filled)
+	assert.Equal(t, "0.9500", s.Points[1].Rate.StringFixed(4))
+	assert.True(t, s.Points[2].Filled)
+	assert.Equal(t, "0.9000", s.Points[2].Rate.StringFixed(4))
+	assert.True(t, s.Points[3].Filled)
+	assert.Equal(t, seriesDay(4), s.Points[3].Date)
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// statsPrecision — знаков после запятой в логарифмах, средних и корне.
+const statsPrecision = 16
+
+type RateStats struct {
+	Base    CurrencyCode    `json:"base"`
+	Quote   CurrencyCode    `json:"quote"`
+	From    Date            `json:"from"`
+	To      Date            `json:"to"`
+	Count   int             `json:"count"`
+	Min     decimal.Decimal `json:"min"`
+	MinDate Date            `json:"min_date"`
+	Max     decimal.Decimal `json:"max"`
+	MaxDate Date            `json:"max_date"`
+	Mean    decimal.Decimal `json:"mean"`
+	Median  decimal.Decimal `json:"median"`
+	// Volatility — выборочное стандартное отклонение дневных логарифмических доходностей.
+	// Нужно хотя бы три курса, иначе nil.
+	Volatility *decimal.Decimal `json:"volatility,omitempty"`
+}
+
+// GetStats считает статистику курса base->quote за [from, to] по сохранённой истории.
+// Берутся только дни, на которые курс есть: перенесённые значения исказили бы доходности.
+func (s *RateConverter) GetStats(ctx context.Context, base, quote CurrencyCode, from, to Date) (RateStats, error) {
+	series, err := s.GetSeries(ctx, base, quote, from, to, FillSkip)
+	if err != nil {
+		return RateStats{}, err
+	}
+	points := series.Points
+	if len(points) == 0 {
+		return RateStats{}, errors.New("no rates in range")
+	}
+
+	out := RateStats{Base: base, Quote: quote, From: from, To: to, Count: len(points)}
+	out.Min, out.MinDate = points[0].Rate, points[0].Date
+	out.Max, out.MaxDate = points[0].Rate, points[0].Date
+
+	rates := make([]decimal.Decimal, len(points))
+	sum := decimal.Zero
+	for i, p := range points {
+		rates[i] = p.Rate
+		sum = sum.Add(p.Rate)
+		if p.Rate.LessThan(out.Min) {
+			out.Min, out.MinDate = p.Rate, p.Date
+		}
+		if p.Rate.GreaterThan(out.Max) {
+			out.Max, out.MaxDate = p.Rate, p.Date
+		}
+	}
+	n := decimal.NewFromInt(int64(len(points)))
+	out.Mean = sum.DivRound(n, statsPrecision)
+	out.Median = median(rates)
+
+	out.Volatility, err = volatility(rates)
+	if err != nil {
+		return RateStats{}, err
+	}
+	return out, nil
+}
+
+func median(v []decimal.Decimal) decimal.Decimal {
+	sorted := append([]decimal.Decimal(nil), v...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return sorted[mid-1].Add(sorted[mid]).DivRound(decimal.NewFromInt(2), statsPrecision)
+}
+
+// volatility — выборочное стандартное отклонение ln(p[i]/p[i-1]).
+func volatility(rates []decimal.Decimal) (*decimal.Decimal, error) {
+	if len(rates) < 3 {
+		return nil, nil
+	}
+
+	returns := make([]decimal.Decimal, 0, len(rates)-1)
+	sum := decimal.Zero
+	for i := 1; i < len(rates); i++ {
+		if !rates[i-1].IsPositive() || !rates[i].IsPositive() {
+			return nil, errors.New("rate must be positive to compute log returns")
+		}
+		r, err := rates[i].DivRound(rates[i-1], statsPrecision).Ln(statsPrecision)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, r)
+		sum = sum.Add(r)
+	}
+	mean := sum.DivRound(decimal.NewFromInt(int64(len(returns))), statsPrecision)
+
+	sq := decimal.Zero
+	for _, r := range returns {
+		d := r.Sub(mean)
+		sq = sq.Add(d.Mul(d))
+	}
+	variance := sq.DivRound(decimal.NewFromInt(int64(len(returns)-1)), statsPrecision)
+	if variance.IsZero() {
+		return &variance, nil
+	}
+
+	sd, err := variance.PowWithPrecision(decimal.NewFromFloat(0.5), statsPrecision)
+	if err != nil {
+		return nil, err
+	}
+	sd = sd.Round(statsPrecision)
+	return &sd, nil
+}
@@ -0,0 +1,61 @@
+package internal_test
+
+import (
+	"context"
+	"service-currency/internal"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"service-currency/internal/mock"
+)
+
+func TestRateConverter_GetStats(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	var rows []internal.CurrencyLatestRate
+	for i, rate := range []string{"100", "110", "99", "110"} {
+		d := seriesDay(i + 1)
+		rows = append(rows, internal.CurrencyLatestRate{
+			BaseCCY: internal.RUB, QuoteCCY: internal.USD, Rate: decimal.RequireFromString(rate), AsOfDate: &d,
+		})
+	}
+	mockStorage.EXPECT().
+		GetRange(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD}, seriesDay(1), seriesDay(5)).
+		Return(rows, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	s, err := converter.GetStats(context.Background(), internal.RUB, internal.USD, seriesDay(1), seriesDay(5))
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, s.Count)
+	assert.Equal(t, "99", s.Min.String())
+	assert.Equal(t, seriesDay(3), s.MinDate)
+	assert.Equal(t, "110", s.Max.String())
+	assert.Equal(t, seriesDay(2), s.MaxDate)
+	assert.Equal(t, "104.75", s.Mean.String())
+	assert.Equal(t, "105", s.Median.String())
+
+	// ln(1.1), ln(0.9), ln(10/9): выборочное σ ≈ 0.1189
+	require.NotNil(t, s.Volatility)
+	assert.Equal(t, "0.1189", s.Volatility.StringFixed(4))
+}
+
+func TestRateConverter_GetStats_Empty(t *testing.T) {
+	mockStorage := mock.NewMockStorage(t)
+
+	mockStorage.EXPECT().
+		GetRange(testifymock.Anything, internal.RUB, []internal.CurrencyCode{internal.USD}, seriesDay(1), seriesDay(5)).
+		Return(nil, nil).
+		Once()
+
+	converter := internal.NewRateConverter(mockStorage)
+	_, err := converter.GetStats(context.Background(), internal.RUB, internal.USD, seriesDay(1), seriesDay(5))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no rates in range")
+}